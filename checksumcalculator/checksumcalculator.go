@@ -22,11 +22,7 @@ func NewChecksumCalculator(patterns []string) *ChecksumCalculator {
 
 //SuggestTalismanRC returns the suggestion for .talismanrc format
 func (cc *ChecksumCalculator) SuggestTalismanRC() string {
-	wd, _ := os.Getwd()
-	repo := git_repo.RepoLocatedAt(wd)
-	gitTrackedFilesAsAdditions := repo.TrackedFilesAsAdditions()
-	//Adding staged files for calculation
-	gitTrackedFilesAsAdditions = append(gitTrackedFilesAsAdditions, repo.StagedAdditions()...)
+	gitTrackedFilesAsAdditions := repoAdditions()
 	var fileIgnoreConfigs []detector.FileIgnoreConfig
 	result := ""
 	for _, pattern := range cc.fileNamePatterns {
@@ -45,6 +41,33 @@ func (cc *ChecksumCalculator) SuggestTalismanRC() string {
 	return result
 }
 
+//CalculateChecksums returns the collective checksum of each of the patterns, as suggested by SuggestTalismanRC.
+//The patterns that no longer match any file of the working tree are left out.
+func (cc *ChecksumCalculator) CalculateChecksums() map[string]string {
+	var existingAdditions []git_repo.Addition
+	for _, addition := range repoAdditions() {
+		if _, err := os.Stat(string(addition.Path)); err == nil {
+			existingAdditions = append(existingAdditions, addition)
+		}
+	}
+	checksums := map[string]string{}
+	for _, pattern := range cc.fileNamePatterns {
+		if collectiveChecksum := cc.calculateCollectiveChecksumForPattern(pattern, existingAdditions); collectiveChecksum != "" {
+			checksums[pattern] = collectiveChecksum
+		}
+	}
+	return checksums
+}
+
+//repoAdditions returns the tracked and staged files of the repository in the working directory
+func repoAdditions() []git_repo.Addition {
+	wd, _ := os.Getwd()
+	repo := git_repo.RepoLocatedAt(wd)
+	gitTrackedFilesAsAdditions := repo.TrackedFilesAsAdditions()
+	//Adding staged files for calculation
+	return append(gitTrackedFilesAsAdditions, repo.StagedAdditions()...)
+}
+
 func (cc *ChecksumCalculator) calculateCollectiveChecksumForPattern(fileNamePattern string, additions []git_repo.Addition) string {
 	var patternpaths []string
 	currentCollectiveChecksum := ""
@@ -56,7 +79,7 @@ func (cc *ChecksumCalculator) calculateCollectiveChecksumForPattern(fileNamePatt
 	// Calculate current collective checksum
 	patternpaths = utility.UniqueItems(patternpaths)
 	if len(patternpaths) != 0 {
-		currentCollectiveChecksum = utility.CollectiveSHA256Hash(patternpaths)
+		currentCollectiveChecksum = utility.CollectiveChecksum(patternpaths)
 	}
 	return currentCollectiveChecksum
 }
@@ -0,0 +1,73 @@
+package detector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//reportedFinding is a finding to be reported, which may stand for a run of findings on adjacent lines, such as the lines of a PEM block
+type reportedFinding struct {
+	text    string
+	finding Finding
+}
+
+//message returns the message of the finding, naming the lines it spans when it stands for findings on more than one line
+func (r reportedFinding) message(output string) string {
+	message := fmt.Sprintf(output, r.text)
+	if r.finding.EndLine > r.finding.Line {
+		message = message + fmt.Sprintf(" (lines %d to %d)", r.finding.Line, r.finding.EndLine)
+	}
+	return message
+}
+
+//reportedFindings returns the findings to report for the texts found in the data.
+//When merging adjacent findings, the findings on the same or adjacent lines are reported as a single finding, named after the first of them.
+//The text of such a finding is the data from the first finding to the last one, so that it can be redacted as a whole.
+func reportedFindings(data []byte, texts []string, mergeAdjacent bool) []reportedFinding {
+	type locatedFinding struct {
+		start, end int
+		reportedFinding
+	}
+	var located []locatedFinding
+	var unlocated []reportedFinding
+	for _, text := range texts {
+		if text == "" {
+			continue
+		}
+		finding := findingIn(data, text)
+		start := strings.Index(string(data), text)
+		if !mergeAdjacent || start == -1 {
+			unlocated = append(unlocated, reportedFinding{text, finding})
+			continue
+		}
+		located = append(located, locatedFinding{start, start + len(text), reportedFinding{text, finding}})
+	}
+	sort.SliceStable(located, func(i, j int) bool { return located[i].start < located[j].start })
+	var merged []locatedFinding
+	for _, current := range located {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			lastLine := last.finding.Line
+			if last.finding.EndLine > lastLine {
+				lastLine = last.finding.EndLine
+			}
+			if current.finding.Line <= lastLine+1 {
+				if current.end > last.end {
+					last.end = current.end
+				}
+				if current.finding.Line > lastLine {
+					last.finding.EndLine = current.finding.Line
+				}
+				last.finding.Text = string(data[last.start:last.end])
+				continue
+			}
+		}
+		merged = append(merged, current)
+	}
+	var result []reportedFinding
+	for _, finding := range merged {
+		result = append(result, finding.reportedFinding)
+	}
+	return append(result, unlocated...)
+}
@@ -0,0 +1,75 @@
+package detector
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	mathrand "math/rand"
+	"strings"
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//pemBlock returns a PEM block whose body spans exactly twenty lines of base64
+func pemBlock() string {
+	body := make([]byte, 20*48)
+	mathrand.New(mathrand.NewSource(42)).Read(body)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: body}))
+}
+
+func TestShouldReportAPEMBlockAsASingleFindingSpanningItsLines(t *testing.T) {
+	content := "config: value\n" + pemBlock()
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("server.key.txt", []byte(content))}
+
+	NewFileContentDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	failures := results.GetFailures("server.key.txt")
+	if assert.Len(t, failures, 1) {
+		assert.Equal(t, 3, failures[0].Line)
+		assert.Equal(t, 22, failures[0].EndLine)
+		assert.Contains(t, failures[0].Message, "(lines 3 to 22)")
+		assert.Equal(t, 20, len(strings.Split(failures[0].Secret, "\n")))
+	}
+}
+
+func TestShouldReportEachLineOfAPEMBlockWhenMergingAdjacentFindingsIsDisabled(t *testing.T) {
+	merge := false
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("server.key.txt", []byte(pemBlock()))}
+
+	NewFileContentDetector().Test(additions, TalismanRCIgnore{MergeAdjacentFindings: &merge}, results)
+
+	failures := results.GetFailures("server.key.txt")
+	assert.Len(t, failures, 20)
+	for _, failure := range failures {
+		assert.Equal(t, 0, failure.EndLine)
+	}
+}
+
+func TestShouldNotMergeFindingsSeparatedByOtherLines(t *testing.T) {
+	first := make([]byte, 48)
+	second := make([]byte, 48)
+	source := mathrand.New(mathrand.NewSource(7))
+	source.Read(first)
+	source.Read(second)
+	content := "a: " + base64.StdEncoding.EncodeToString(first) + "\nplain text\nb: " + base64.StdEncoding.EncodeToString(second) + "\n"
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("config.yml", []byte(content))}
+
+	NewFileContentDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	failures := results.GetFailures("config.yml")
+	if assert.Len(t, failures, 2) {
+		assert.Equal(t, 1, failures[0].Line)
+		assert.Equal(t, 3, failures[1].Line)
+		assert.NotContains(t, failures[0].Message, "lines")
+	}
+}
+
+func TestShouldReadMergeAdjacentFindingsFromTheConfig(t *testing.T) {
+	assert.True(t, NewTalismanRCIgnore([]byte("")).MergesAdjacentFindings())
+	assert.False(t, NewTalismanRCIgnore([]byte("merge_adjacent_findings: false\n")).MergesAdjacentFindings())
+}
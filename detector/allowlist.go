@@ -0,0 +1,209 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//Names of the detectors, as used to configure the content detectors in .talismanrc and to assert that the detectors executed
+const (
+	FileNameDetectorName         = "filename"
+	FileContentDetectorName      = "filecontent"
+	Base64DetectorName           = "base64"
+	HexDetectorName              = "hex"
+	URLSafeDetectorName          = "urlsafe"
+	CreditCardDetectorName       = "creditcard"
+	PatternDetectorName          = "pattern"
+	RegistryTokenDetectorName    = "registry"
+	KnownTokenDetectorName       = "knowntoken"
+	CIPipelineDetectorName       = "cipipeline"
+	LogStatementDetectorName     = "logstatement"
+	EnvDumpDetectorName          = "envdump"
+	WeakCredentialDetectorName   = "weakcredential"
+	KeyPathDetectorName          = "keypath"
+	OAuthTokenDetectorName       = "oauth"
+	NetrcDetectorName            = "netrc"
+	AnsibleVaultDetectorName     = "ansiblevault"
+	ShellHistoryDetectorName     = "shellhistory"
+	HTTPFixtureDetectorName      = "httpfixture"
+	MetadataFileDetectorName     = "metadatafile"
+	SuppressedSecretDetectorName = "suppressedsecret"
+	ProtoDetectorName            = "proto"
+)
+
+//DetectorConfig represents the settings of a single detector in .talismanrc
+//Findings whose value is shorter than the MinValueLength are considered to be placeholders, and are not reported
+//The Wordlist extends the words of the detectors that match values against a wordlist, such as the weakcredential detector
+//Opt-in detectors, such as the keypath detector, only run when Enabled
+//The Paths are the files that a detector expects to be protected, such as the files that the ansiblevault detector expects to be encrypted
+type DetectorConfig struct {
+	Enabled         bool     `yaml:"enabled,omitempty"`
+	AllowedPatterns []string `yaml:"allowed_patterns,omitempty"`
+	MinValueLength  int      `yaml:"min_value_length,omitempty"`
+	Wordlist        []string `yaml:"wordlist,omitempty"`
+	Paths           []string `yaml:"paths,omitempty"`
+}
+
+//assignmentPattern matches a finding made of a key and the value assigned to it, such as password = "secret"
+var assignmentPattern = regexp.MustCompile(`^\s*["'_]?[A-Za-z][\w.-]*["']?\s*[:=]\s*([^=\s].*)$`)
+
+//elementPattern matches the value of a finding made of an XML element, such as <password>secret</password>
+var elementPattern = regexp.MustCompile(`>([^<]*)</`)
+
+//AllowList holds the allowed patterns of a config compiled once, both the global ones and those of each detector
+type AllowList struct {
+	global    []allowedPattern
+	detectors map[string][]allowedPattern
+}
+
+//allowedPattern is an allowed pattern as written in the config, along with its compiled regexp
+type allowedPattern struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+//AllowList compiles the global and per detector allowed_patterns of the config, failing on the first pattern that is not a valid regexp
+func (i TalismanRCIgnore) AllowList() (AllowList, error) {
+	allowList := AllowList{detectors: map[string][]allowedPattern{}}
+	for _, pattern := range i.AllowedPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return AllowList{}, fmt.Errorf("invalid allowed_patterns: unable to parse %q: %v", pattern, err)
+		}
+		allowList.global = append(allowList.global, allowedPattern{pattern, re})
+	}
+	for name, detectorConfig := range i.Detectors {
+		for _, pattern := range detectorConfig.AllowedPatterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return AllowList{}, fmt.Errorf("invalid allowed_patterns of the %s detector: unable to parse %q: %v", name, pattern, err)
+			}
+			allowList.detectors[name] = append(allowList.detectors[name], allowedPattern{pattern, re})
+		}
+	}
+	return allowList, nil
+}
+
+//allowingPattern returns the first allowed pattern that the value found by the named detector matches
+func (a AllowList) allowingPattern(detectorName string, value string) (string, bool) {
+	for _, patterns := range [][]allowedPattern{a.global, a.detectors[detectorName]} {
+		for _, allowed := range patterns {
+			if allowed.re.MatchString(value) {
+				return allowed.pattern, true
+			}
+		}
+	}
+	return "", false
+}
+
+//IsAllowed answers true if the value found by the named detector matches one of the global allowed patterns or one of the allowed patterns of that detector
+func (i TalismanRCIgnore) IsAllowed(detectorName string, value string) bool {
+	allowList, _ := i.AllowList()
+	_, allowed := allowList.allowingPattern(detectorName, value)
+	return allowed
+}
+
+//isAllowedIn is like IsAllowed, but also records the allowed pattern that matched in the audit log of the results, against the file the value was found in
+func (i TalismanRCIgnore) isAllowedIn(detectorName string, filePath git_repo.FilePath, value string, result *DetectionResults) bool {
+	pattern, allowed := result.allowListFor(i).allowingPattern(detectorName, value)
+	if allowed {
+		result.audit.record(AuditRecord{Path: string(filePath), Event: AuditSuppressed, Detector: detectorName, Category: "filecontent", Rule: "allowed_patterns", Pattern: pattern})
+	}
+	return allowed
+}
+
+//IsTooShort answers true if the value of the finding of the named detector is shorter than the min_value_length configured for that detector
+func (i TalismanRCIgnore) IsTooShort(detectorName string, finding string) bool {
+	minValueLength := i.Detectors[detectorName].MinValueLength
+	return minValueLength > 0 && len([]rune(assignedValue(finding))) < minValueLength
+}
+
+//reportableFindings returns the findings of the named detector in the file that are neither allowed by the config nor too short to be secrets
+func (i TalismanRCIgnore) reportableFindings(detectorName string, filePath git_repo.FilePath, findings []string, results *DetectionResults) []string {
+	var reportable []string
+	for _, finding := range findings {
+		if i.isReportable(detectorName, filePath, finding, results) {
+			reportable = append(reportable, finding)
+		}
+	}
+	return reportable
+}
+
+//isReportable answers true if the finding of the named detector in the file is neither allowed by the config nor too short to be a secret
+func (i TalismanRCIgnore) isReportable(detectorName string, filePath git_repo.FilePath, finding string, results *DetectionResults) bool {
+	if finding == "" || i.isAllowedIn(detectorName, filePath, finding, results) || i.isTooShortIn(detectorName, filePath, finding, results) {
+		log.WithFields(log.Fields{
+			"detector": detectorName,
+			"finding":  finding,
+		}).Debug("Skipping finding as it matches an allowed pattern or its value is too short.")
+		return false
+	}
+	return true
+}
+
+//isTooShortIn is like IsTooShort, but also records the min_value_length in the audit log of the results, against the file the value was found in
+func (i TalismanRCIgnore) isTooShortIn(detectorName string, filePath git_repo.FilePath, finding string, result *DetectionResults) bool {
+	tooShort := i.IsTooShort(detectorName, finding)
+	if tooShort {
+		result.audit.record(AuditRecord{Path: string(filePath), Event: AuditSuppressed, Detector: detectorName, Category: "filecontent", Rule: "min_value_length", Pattern: strconv.Itoa(i.Detectors[detectorName].MinValueLength)})
+	}
+	return tooShort
+}
+
+//assignedValue returns the value assigned in the finding, without its quotes. Findings that are not assignments are returned as they are.
+func assignedValue(finding string) string {
+	if match := elementPattern.FindStringSubmatch(finding); match != nil {
+		return strings.TrimSpace(match[1])
+	}
+	match := assignmentPattern.FindStringSubmatch(finding)
+	if match == nil {
+		return finding
+	}
+	value := match[1]
+	if quote := value[0]; quote == '"' || quote == '\'' {
+		if end := strings.IndexByte(value[1:], quote); end != -1 {
+			return value[1 : end+1]
+		}
+		return value[1:]
+	}
+	if end := strings.IndexAny(value, " \t,;"); end != -1 {
+		return value[:end]
+	}
+	return value
+}
+
+func mergeDetectorConfigs(configs ...map[string]DetectorConfig) map[string]DetectorConfig {
+	var result map[string]DetectorConfig
+	for _, config := range configs {
+		for name, detectorConfig := range config {
+			if result == nil {
+				result = map[string]DetectorConfig{}
+			}
+			merged := result[name]
+			merged.AllowedPatterns = append(merged.AllowedPatterns, detectorConfig.AllowedPatterns...)
+			merged.Wordlist = append(merged.Wordlist, detectorConfig.Wordlist...)
+			merged.Paths = append(merged.Paths, detectorConfig.Paths...)
+			merged.Enabled = merged.Enabled || detectorConfig.Enabled
+			if detectorConfig.MinValueLength != 0 {
+				merged.MinValueLength = detectorConfig.MinValueLength
+			}
+			result[name] = merged
+		}
+	}
+	return result
+}
+
+//allowListFor returns the allow list compiled by the chain, or compiles the one of the config for a detector that is run on its own
+func (r *DetectionResults) allowListFor(ignoreConfig TalismanRCIgnore) AllowList {
+	if r.allowList != nil {
+		return *r.allowList
+	}
+	allowList, _ := ignoreConfig.AllowList()
+	return allowList
+}
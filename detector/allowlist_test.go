@@ -0,0 +1,98 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const allowlistedLine = "password: U2FtcGxlVGVzdEZpeHR1cmVWYWx1ZUZvckRvY3MxMjM0NTY="
+
+func TestShouldAllowAValueOnlyForTheConfiguredDetector(t *testing.T) {
+	additions := []git_repo.Addition{git_repo.NewAddition("config.yml", []byte(allowlistedLine))}
+	ignores := NewTalismanRCIgnore([]byte(`detectors:
+  base64:
+    allowed_patterns:
+    - ^U2FtcGxl
+`))
+
+	contentResults := NewDetectionResults()
+	NewFileContentDetector().Test(additions, ignores, contentResults)
+	assert.False(t, contentResults.HasFailures(), "Expected the value to be allowed for the base64 detector")
+
+	patternResults := NewDetectionResults()
+	NewPatternDetector().Test(additions, ignores, patternResults)
+	assert.True(t, patternResults.HasFailures(), "Expected the value to still be flagged by the pattern detector")
+}
+
+func TestShouldAllowAValueForAllDetectorsWithAGlobalPattern(t *testing.T) {
+	additions := []git_repo.Addition{git_repo.NewAddition("config.yml", []byte(allowlistedLine))}
+	ignores := NewTalismanRCIgnore([]byte("allowed_patterns:\n- U2FtcGxlVGVzdEZpeHR1cmVWYWx1ZUZvckRvY3MxMjM0NTY=\n"))
+	results := NewDetectionResults()
+
+	NewFileContentDetector().Test(additions, ignores, results)
+	NewPatternDetector().Test(additions, ignores, results)
+
+	assert.False(t, results.HasFailures(), "Expected the value to be allowed for all detectors")
+}
+
+func TestShouldFlagValuesWithoutAllowedPatterns(t *testing.T) {
+	additions := []git_repo.Addition{git_repo.NewAddition("config.yml", []byte(allowlistedLine))}
+	results := NewDetectionResults()
+
+	NewFileContentDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.True(t, results.HasFailures(), "Expected the base64 text to be flagged")
+}
+
+func TestShouldCombineGlobalAndDetectorAllowedPatternsWhenMerging(t *testing.T) {
+	rc := NewTalismanRCIgnore([]byte("allowed_patterns: [foo]\ndetectors:\n  hex:\n    allowed_patterns: [bar]\n"))
+	other := NewTalismanRCIgnore([]byte("detectors:\n  hex:\n    allowed_patterns: [baz]\n"))
+
+	merged := rc.MergeWith(other)
+
+	assert.True(t, merged.IsAllowed(HexDetectorName, "foo"))
+	assert.True(t, merged.IsAllowed(HexDetectorName, "bar"))
+	assert.True(t, merged.IsAllowed(HexDetectorName, "baz"))
+	assert.False(t, merged.IsAllowed(Base64DetectorName, "baz"))
+}
+
+func TestShouldIgnoreValuesShorterThanTheMinValueLength(t *testing.T) {
+	ignores := NewTalismanRCIgnore([]byte("detectors:\n  pattern:\n    min_value_length: 8\n"))
+	placeholder := []git_repo.Addition{git_repo.NewAddition("config.py", []byte(`password = "x" # replaced at deploy time`))}
+	secret := []git_repo.Addition{git_repo.NewAddition("config.py", []byte(`password = "realLongSecretValue"`))}
+
+	placeholderResults := NewDetectionResults()
+	NewPatternDetector().Test(placeholder, ignores, placeholderResults)
+	assert.False(t, placeholderResults.HasFailures(), "Expected the short placeholder value to be ignored")
+
+	secretResults := NewDetectionResults()
+	NewPatternDetector().Test(secret, ignores, secretResults)
+	assert.True(t, secretResults.HasFailures(), "Expected the long value to be flagged")
+
+	unconfiguredResults := NewDetectionResults()
+	NewPatternDetector().Test(placeholder, TalismanRCIgnore{}, unconfiguredResults)
+	assert.True(t, unconfiguredResults.HasFailures(), "Expected short values to be flagged without a min_value_length")
+}
+
+func TestShouldFindTheAssignedValueOfAFinding(t *testing.T) {
+	assert.Equal(t, "x", assignedValue(`password = "x" # replaced at deploy time`))
+	assert.Equal(t, "secret", assignedValue(`"pwd": 'secret', user`))
+	assert.Equal(t, "hunter22", assignedValue(`PWD=hunter22 ./run.sh`))
+	assert.Equal(t, "jdghfakjkdha", assignedValue(`<password data=123> jdghfakjkdha</password>`))
+	assert.Equal(t, "U2FtcGxlVGVzdA==", assignedValue("U2FtcGxlVGVzdA=="))
+}
+
+func TestInvalidAllowedPatternsShouldBeReportedAsConfigErrors(t *testing.T) {
+	for contents, expected := range map[string]string{
+		"allowed_patterns:\n- 'key-(['\n":                          "invalid allowed_patterns: unable to parse \"key-([\": error parsing regexp: missing closing ]: `[`",
+		"detectors:\n  hex:\n    allowed_patterns:\n    - 'a(b'\n": "invalid allowed_patterns of the hex detector: unable to parse \"a(b\": error parsing regexp: missing closing ): `a(b`",
+	} {
+		_, err := NewTalismanRCIgnore([]byte(contents)).AllowList()
+		if assert.Error(t, err, contents) {
+			assert.Equal(t, expected, err.Error())
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const ansibleVaultHeader = "$ANSIBLE_VAULT"
+
+//ansibleVarsPathPattern matches the files holding the variables of Ansible groups and hosts, including those in a directory named after the group or host
+var ansibleVarsPathPattern = regexp.MustCompile(`(^|/)(group_vars|host_vars)/([^/]+/)?[^/]+$`)
+
+//ansibleVaultFileNames are the names that the vars files meant to be encrypted are given by convention, such as group_vars/all/vault.yml
+var ansibleVaultFileNames = []string{"vault", "vault.yml", "vault.yaml"}
+
+//AnsibleVaultDetector flags the Ansible vars files that are expected to be encrypted with ansible-vault but are not, and the secrets held in plaintext vars files
+type AnsibleVaultDetector struct{}
+
+//NewAnsibleVaultDetector returns an AnsibleVaultDetector
+func NewAnsibleVaultDetector() *AnsibleVaultDetector {
+	return &AnsibleVaultDetector{}
+}
+
+//Test tests the Ansible vars files among the Additions to ensure that they are encrypted when expected to be, and that they don't hold plaintext secrets.
+//The vault files of group_vars and host_vars are expected to be encrypted, as well as the files matched by the paths of the ansiblevault detector config.
+func (ad *AnsibleVaultDetector) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	for _, addition := range additions {
+		expectedEncrypted := isExpectedVaultFile(addition, ignoreConfig.Detectors[AnsibleVaultDetectorName].Paths)
+		if !expectedEncrypted && !ansibleVarsPathPattern.MatchString(string(addition.Path)) {
+			continue
+		}
+		if isVaultEncrypted(addition.Data) {
+			continue
+		}
+		if ignoreConfig.Deny(addition, "filecontent") || cc.IsScanNotRequired(addition) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Ignoring addition as it was specified to be ignored.")
+			result.Ignore(addition.Path, "filecontent")
+			continue
+		}
+		if expectedEncrypted && strings.TrimSpace(string(addition.Data)) != "" {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Failing file as it is expected to be encrypted with ansible-vault.")
+			result.Fail(addition.Path, "filecontent", fmt.Sprintf("Expected file to be encrypted with ansible-vault, but it does not start with %s", ansibleVaultHeader), addition.Commits, HighSeverity)
+		}
+		for _, line := range ignoreConfig.reportableFindings(AnsibleVaultDetectorName, addition.Path, plaintextAnsibleSecrets(string(addition.Data)), result) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Failing file as it holds a secret in plaintext Ansible vars.")
+			result.FailAt(addition.Path, "filecontent", fmt.Sprintf("Expected file to not to contain plaintext Ansible vars such as: %s", line), addition.Commits, HighSeverity, findingIn(addition.Data, line))
+		}
+	}
+}
+
+func isExpectedVaultFile(addition git_repo.Addition, paths []string) bool {
+	if ansibleVarsPathPattern.MatchString(string(addition.Path)) && contains(ansibleVaultFileNames, string(addition.Name)) {
+		return true
+	}
+	for _, pattern := range paths {
+		if pattern != "" && addition.Matches(pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func isVaultEncrypted(data []byte) bool {
+	return strings.HasPrefix(strings.TrimLeft(string(data), " \t\r\n"), ansibleVaultHeader)
+}
+
+//plaintextAnsibleSecrets returns the lines of the vars that assign a literal value to a secret-named variable.
+//Values referring to other variables, such as {{ vault_db_password }}, and values encrypted inline with !vault are not secrets held in plaintext.
+func plaintextAnsibleSecrets(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		match := ciAssignmentPattern.FindStringSubmatch(line)
+		if match == nil || !ciSecretNamePattern.MatchString(match[1]) {
+			continue
+		}
+		value := strings.TrimSpace(match[2])
+		if strings.Contains(value, "{{") || strings.HasPrefix(value, "!vault") || value == "|" || value == ">" {
+			continue
+		}
+		if isPopulatedCredential(value) {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,81 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const encryptedVault = `$ANSIBLE_VAULT;1.1;AES256
+62313365396662343061393464336163383764373764613633653634306231386433626436623361
+6134333665353966363534333632666535333761666131620a663537646436643839616531643561
+`
+
+func TestShouldFailUnencryptedVaultFilesWithPlaintextSecrets(t *testing.T) {
+	results := NewDetectionResults()
+	content := []byte("vault_db_user: app\nvault_db_password: Xk82mfQpz01\n")
+	additions := []git_repo.Addition{git_repo.NewAddition("inventory/group_vars/production/vault.yml", content)}
+
+	NewAnsibleVaultDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	failures := results.GetFailures("inventory/group_vars/production/vault.yml")
+	if assert.Len(t, failures, 2) {
+		assert.Equal(t, "Expected file to be encrypted with ansible-vault, but it does not start with $ANSIBLE_VAULT", failures[0].Message)
+		assert.Equal(t, "Expected file to not to contain plaintext Ansible vars such as: vault_db_password: Xk82mfQpz01", failures[1].Message)
+		assert.Equal(t, HighSeverity, failures[1].Severity)
+		assert.Equal(t, 2, failures[1].Line)
+	}
+}
+
+func TestShouldNotFlagVaultEncryptedFiles(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{
+		git_repo.NewAddition("group_vars/all/vault.yml", []byte(encryptedVault)),
+		git_repo.NewAddition("host_vars/db01.yml", []byte(encryptedVault)),
+	}
+
+	NewAnsibleVaultDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasDetectionMessages(), "Expected vault encrypted files to pass")
+}
+
+func TestShouldAllowReferencesAndInlineVaultValuesInPlaintextVars(t *testing.T) {
+	results := NewDetectionResults()
+	content := []byte(`db_user: app
+db_password: "{{ vault_db_password }}"
+api_token: !vault |
+  $ANSIBLE_VAULT;1.1;AES256
+  62313365396662343061393464336163383764373764613633653634306231386433626436623361
+smtp_password: ""
+`)
+	additions := []git_repo.Addition{git_repo.NewAddition("group_vars/all/vars.yml", content)}
+
+	NewAnsibleVaultDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasDetectionMessages(), "Expected references and inline vault values to pass")
+}
+
+func TestShouldExpectTheConfiguredPathsToBeEncrypted(t *testing.T) {
+	results := NewDetectionResults()
+	ignores := NewTalismanRCIgnore([]byte("detectors:\n  ansiblevault:\n    paths:\n    - roles/*/vars/secrets.yml\n"))
+	additions := []git_repo.Addition{
+		git_repo.NewAddition("roles/web/vars/secrets.yml", []byte("tls_cert_name: web\n")),
+		git_repo.NewAddition("roles/web/vars/main.yml", []byte("tls_cert_name: web\n")),
+	}
+
+	NewAnsibleVaultDetector().Test(additions, ignores, results)
+
+	assert.Len(t, results.GetFailures("roles/web/vars/secrets.yml"), 1, "Expected the configured path to be expected to be encrypted")
+	assert.Len(t, results.GetFailures("roles/web/vars/main.yml"), 0, "Expected files outside of the vars and configured paths not to be scanned")
+}
+
+func TestShouldNotScanFilesOutsideOfTheAnsibleVars(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("config/vault.yml", []byte("db_password: Xk82mfQpz01\n"))}
+
+	NewAnsibleVaultDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasDetectionMessages(), "Expected files outside of group_vars and host_vars to be left alone")
+}
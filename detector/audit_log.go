@@ -0,0 +1,150 @@
+package detector
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"talisman/git_repo"
+)
+
+const (
+	//AuditScanned records the detectors that scanned an addition
+	AuditScanned = "scanned"
+	//AuditReported records a finding that was reported, as a failure or as a warning
+	AuditReported = "reported"
+	//AuditSuppressed records a finding, or a whole addition for a detector, that a rule of the config kept from being reported
+	AuditSuppressed = "suppressed"
+)
+
+//AuditRecord is a single decision taken on an addition, as recorded in the audit log.
+//For suppressions, the Rule is the kind of rule that applied, such as fileignoreconfig, checksum, allowed_patterns, min_value_length,
+//suppress, severity_actions, scopeconfig or manifest, and the Pattern is the part of the rule that matched.
+//The values allowed by a rule are not recorded, and the text of each finding is masked in its Message, so that the log does not hold the secrets it audits.
+type AuditRecord struct {
+	Path      string   `json:"path"`
+	Event     string   `json:"event"`
+	Detectors []string `json:"detectors,omitempty"`
+	Detector  string   `json:"detector,omitempty"`
+	Category  string   `json:"category,omitempty"`
+	Action    string   `json:"action,omitempty"`
+	Severity  string   `json:"severity,omitempty"`
+	Message   string   `json:"message,omitempty"`
+	Line      int      `json:"line,omitempty"`
+	Rule      string   `json:"rule,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	Source    string   `json:"source,omitempty"`
+	Reason    string   `json:"reason,omitempty"`
+}
+
+//auditLog collects the audit records of a run. It is shared by the DetectionResults and the config passed to the detectors,
+//so that the decisions taken by the config, such as allowing a value, are recorded along with those taken by the results.
+type auditLog struct {
+	records []AuditRecord
+}
+
+func (l *auditLog) record(record AuditRecord) {
+	if l != nil {
+		l.records = append(l.records, record)
+	}
+}
+
+//fileIgnoreRecord records that the file ignore kept the addition from being scanned by the detector
+func fileIgnoreRecord(filePath git_repo.FilePath, detectorName string, category string, rule string, ignore FileIgnoreConfig) AuditRecord {
+	return AuditRecord{
+		Path:     string(filePath),
+		Event:    AuditSuppressed,
+		Detector: detectorName,
+		Category: category,
+		Rule:     rule,
+		Pattern:  ignore.FileName,
+		Source:   ignore.Source(),
+		Reason:   ignore.Comment,
+	}
+}
+
+//EnableAuditLog makes the results record every decision taken on the additions, to be rendered with AuditLog
+func (r *DetectionResults) EnableAuditLog() {
+	r.audit = &auditLog{}
+}
+
+//AuditRecords returns the decisions recorded since the audit log was enabled
+func (r *DetectionResults) AuditRecords() []AuditRecord {
+	if r.audit == nil {
+		return nil
+	}
+	return r.audit.records
+}
+
+//AuditSkipped records that the additions were left out of the scan by the given rule, before any detector ran
+func (r *DetectionResults) AuditSkipped(additions []git_repo.Addition, rule string) {
+	for _, addition := range additions {
+		r.audit.record(AuditRecord{Path: string(addition.Path), Event: AuditSuppressed, Rule: rule})
+	}
+}
+
+//AuditLog renders the recorded decisions as newline-delimited JSON, one record per line, for ingestion by log pipelines
+func (r *DetectionResults) AuditLog() ([]byte, error) {
+	var log bytes.Buffer
+	for _, record := range r.AuditRecords() {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		log.Write(append(line, '\n'))
+	}
+	return log.Bytes(), nil
+}
+
+//auditDetectorRun records, for each addition passed to the named detector, either the rule that kept the detector from scanning it,
+//or that the detector scanned it
+func (r *DetectionResults) auditDetectorRun(detectorName string, category string, additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, cc *ChecksumCompare, scanned map[git_repo.FilePath][]string) {
+	if r.audit == nil {
+		return
+	}
+	for _, addition := range additions {
+		if ignore, denied := ignoreConfig.denyingRule(addition, category); denied {
+			r.audit.record(fileIgnoreRecord(addition.Path, detectorName, category, "fileignoreconfig", ignore))
+		} else if ignore, matched := cc.checksumRule(addition); matched {
+			r.audit.record(fileIgnoreRecord(addition.Path, detectorName, category, "checksum", ignore))
+		} else {
+			scanned[addition.Path] = append(scanned[addition.Path], detectorName)
+		}
+	}
+}
+
+//auditScanned records the detectors that scanned each addition, once all of them ran
+func (r *DetectionResults) auditScanned(additions []git_repo.Addition, scanned map[git_repo.FilePath][]string) {
+	for _, addition := range additions {
+		if detectors, ok := scanned[addition.Path]; ok {
+			r.audit.record(AuditRecord{Path: string(addition.Path), Event: AuditScanned, Detectors: detectors})
+			delete(scanned, addition.Path)
+		}
+	}
+}
+
+//auditFinding records a finding that was reported with the given action, or suppressed by the given rule
+func (r *DetectionResults) auditFinding(event string, action string, filePath git_repo.FilePath, category string, message string, severity Severity, finding Finding, rule string, pattern string) {
+	r.audit.record(AuditRecord{
+		Path:     string(filePath),
+		Event:    event,
+		Detector: r.currentDetector,
+		Category: category,
+		Action:   action,
+		Severity: severity.String(),
+		Message:  auditedMessage(message, finding),
+		Line:     finding.Line,
+		Rule:     rule,
+		Pattern:  pattern,
+	})
+}
+
+//auditedMessage masks each line of the text of the finding in the message, as the messages of the detectors quote the secrets they found
+func auditedMessage(message string, finding Finding) string {
+	for _, secret := range strings.Split(finding.Text, "\n") {
+		if secret != "" {
+			message = strings.Replace(message, secret, maskSecret(secret), -1)
+		}
+	}
+	return message
+}
@@ -0,0 +1,160 @@
+package detector
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const auditedSecret = "aws_secret_access_key = 'wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY'"
+
+func auditRecordsOf(results *DetectionResults, event string) []AuditRecord {
+	var records []AuditRecord
+	for _, record := range results.AuditRecords() {
+		if record.Event == event {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func TestAuditLogShouldRecordTheFileIgnoreThatSuppressedADetector(t *testing.T) {
+	results := NewDetectionResults()
+	results.EnableAuditLog()
+	ignores := NewTalismanRCIgnore([]byte("fileignoreconfig:\n- filename: config/prod.env\n  ignore_detectors: [filecontent]\n  comment: rotated in JIRA-42\n")).WithSource(".talismanrc")
+
+	NewChain().AddNamedDetector(FileContentDetectorName, "filecontent", NewFileContentDetector()).Test([]git_repo.Addition{
+		git_repo.NewAddition("config/prod.env", []byte(auditedSecret)),
+	}, ignores, results)
+
+	suppressed := auditRecordsOf(results, AuditSuppressed)
+	assert.Equal(t, []AuditRecord{{
+		Path:     "config/prod.env",
+		Event:    AuditSuppressed,
+		Detector: FileContentDetectorName,
+		Category: "filecontent",
+		Rule:     "fileignoreconfig",
+		Pattern:  "config/prod.env",
+		Source:   ".talismanrc",
+		Reason:   "rotated in JIRA-42",
+	}}, suppressed)
+	assert.Empty(t, auditRecordsOf(results, AuditScanned), "Expected the ignored file not to be recorded as scanned")
+}
+
+func TestAuditLogShouldRecordTheReportedFindingsAndTheDetectorsThatScanned(t *testing.T) {
+	results := NewDetectionResults()
+	results.EnableAuditLog()
+
+	NewChain().AddNamedDetector(PatternDetectorName, "filecontent", NewPatternDetector()).Test([]git_repo.Addition{
+		git_repo.NewAddition("config/prod.env", []byte("line one\npassword=example-value\n")),
+	}, TalismanRCIgnore{}, results)
+
+	reported := auditRecordsOf(results, AuditReported)
+	assert.Len(t, reported, 1)
+	assert.Equal(t, "config/prod.env", reported[0].Path)
+	assert.Equal(t, PatternDetectorName, reported[0].Detector)
+	assert.Equal(t, FailAction, reported[0].Action)
+	assert.Equal(t, "high", reported[0].Severity)
+	assert.Equal(t, 2, reported[0].Line)
+	assert.Equal(t, []AuditRecord{{Path: "config/prod.env", Event: AuditScanned, Detectors: []string{PatternDetectorName}}}, auditRecordsOf(results, AuditScanned))
+}
+
+func TestAuditLogShouldRecordTheAllowedPatternThatSuppressedAFinding(t *testing.T) {
+	results := NewDetectionResults()
+	results.EnableAuditLog()
+	ignores := NewTalismanRCIgnore([]byte("detectors:\n  pattern:\n    allowed_patterns:\n    - 'password=example-.*'\n"))
+
+	NewChain().AddNamedDetector(PatternDetectorName, "filecontent", NewPatternDetector()).Test([]git_repo.Addition{
+		git_repo.NewAddition("config/prod.env", []byte("password=example-value\n")),
+	}, ignores, results)
+
+	assert.Empty(t, auditRecordsOf(results, AuditReported))
+	suppressed := auditRecordsOf(results, AuditSuppressed)
+	assert.Len(t, suppressed, 1)
+	assert.Equal(t, "allowed_patterns", suppressed[0].Rule)
+	assert.Equal(t, "password=example-.*", suppressed[0].Pattern)
+	assert.Equal(t, PatternDetectorName, suppressed[0].Detector)
+	assert.NotContains(t, suppressed[0].Message, "example-value", "Expected the allowed value not to be recorded")
+}
+
+func TestAuditLogShouldRecordTheSuppressExpressionThatSuppressedAFinding(t *testing.T) {
+	results := NewDetectionResults()
+	results.EnableAuditLog()
+	ignores := NewTalismanRCIgnore([]byte("suppress:\n- path matches \"test/**\"\n"))
+
+	NewChain().AddNamedDetector(PatternDetectorName, "filecontent", NewPatternDetector()).Test([]git_repo.Addition{
+		git_repo.NewAddition("test/fixture.env", []byte("password=example-value\n")),
+		git_repo.NewAddition("config/prod.env", []byte("password=example-value\n")),
+	}, ignores, results)
+
+	suppressed := auditRecordsOf(results, AuditSuppressed)
+	assert.Len(t, suppressed, 1)
+	assert.Equal(t, "test/fixture.env", suppressed[0].Path)
+	assert.Equal(t, "suppress", suppressed[0].Rule)
+	assert.Equal(t, `path matches "test/**"`, suppressed[0].Pattern)
+	reported := auditRecordsOf(results, AuditReported)
+	assert.Len(t, reported, 1)
+	assert.Equal(t, "config/prod.env", reported[0].Path)
+}
+
+func TestAuditLogShouldRecordTheAdditionsSkippedBeforeTheScan(t *testing.T) {
+	results := NewDetectionResults()
+	results.EnableAuditLog()
+
+	results.AuditSkipped([]git_repo.Addition{git_repo.NewAddition("vendor/lib.go", []byte("code"))}, "scopeconfig")
+
+	assert.Equal(t, []AuditRecord{{Path: "vendor/lib.go", Event: AuditSuppressed, Rule: "scopeconfig"}}, results.AuditRecords())
+}
+
+func TestAuditLogShouldBeRenderedAsOneJSONObjectPerLine(t *testing.T) {
+	results := NewDetectionResults()
+	results.EnableAuditLog()
+
+	NewChain().AddNamedDetector(PatternDetectorName, "filecontent", NewPatternDetector()).Test([]git_repo.Addition{
+		git_repo.NewAddition("config/prod.env", []byte("password=example-value\n")),
+	}, TalismanRCIgnore{}, results)
+	contents, err := results.AuditLog()
+
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	for _, line := range lines {
+		var record AuditRecord
+		assert.NoError(t, json.Unmarshal([]byte(line), &record), "Expected each line to be a JSON record: %s", line)
+		assert.Equal(t, "config/prod.env", record.Path)
+	}
+}
+
+func TestAuditLogShouldNotHoldTheSecretsOfTheFindings(t *testing.T) {
+	results := NewDetectionResults()
+	results.EnableAuditLog()
+	ignores := NewTalismanRCIgnore([]byte("suppress:\n- path matches \"test/**\"\n"))
+
+	DefaultChain().Test([]git_repo.Addition{
+		git_repo.NewAddition("config/prod.env", []byte(auditedSecret+"\npassword=Tr0ub4dor-3xkcd\n")),
+		git_repo.NewAddition("test/fixture.env", []byte(auditedSecret+"\n")),
+	}, ignores, results)
+	contents, _ := results.AuditLog()
+
+	assert.NotEmpty(t, auditRecordsOf(results, AuditReported))
+	assert.NotEmpty(t, auditRecordsOf(results, AuditSuppressed))
+	assert.NotContains(t, string(contents), "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY")
+	assert.NotContains(t, string(contents), "Tr0ub4dor-3xkcd")
+}
+
+func TestAuditLogShouldBeEmptyWhenNotEnabled(t *testing.T) {
+	results := NewDetectionResults()
+
+	NewChain().AddNamedDetector(PatternDetectorName, "filecontent", NewPatternDetector()).Test([]git_repo.Addition{
+		git_repo.NewAddition("config/prod.env", []byte("password=example-value\n")),
+	}, TalismanRCIgnore{}, results)
+	contents, err := results.AuditLog()
+
+	assert.NoError(t, err)
+	assert.Empty(t, contents)
+	assert.Len(t, results.GetFailures("config/prod.env"), 1)
+}
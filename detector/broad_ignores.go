@@ -0,0 +1,51 @@
+package detector
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//maxSampleMatches is the number of the additions matched by an over-broad ignore that are reported along with it
+const maxSampleMatches = 3
+
+//failBroadIgnores fails the config an ignore was read from when the ignore matches more additions than the configured max_files_per_rule,
+//as a glob such as ** effectively disables the scan. Without a max_files_per_rule, ignores can match any number of additions.
+func (i TalismanRCIgnore) failBroadIgnores(additions []git_repo.Addition, result *DetectionResults) {
+	if i.MaxFilesPerRule <= 0 {
+		return
+	}
+	for _, ignore := range i.FileIgnoreConfig {
+		if isEmptyString(ignore.FileName) || ignore.IsExpired(time.Now()) {
+			continue
+		}
+		var matches []string
+		for _, addition := range additions {
+			if ignore.Matches(addition) {
+				matches = append(matches, string(addition.Path))
+			}
+		}
+		if len(matches) <= i.MaxFilesPerRule {
+			continue
+		}
+		source := ignore.Source()
+		if source == "" {
+			source = DefaultRCFileName
+		}
+		samples := matches
+		if len(samples) > maxSampleMatches {
+			samples = samples[:maxSampleMatches]
+		}
+		log.WithFields(log.Fields{
+			"filePath": source,
+			"ignore":   ignore.FileName,
+			"matches":  len(matches),
+		}).Info("Failing config as one of its ignores matches more files than the max files per rule.")
+		message := fmt.Sprintf("Expected the ignore of %s to match at most %d files (max_files_per_rule), but it matched %d files such as: %s", ignore.FileName, i.MaxFilesPerRule, len(matches), strings.Join(samples, ", "))
+		result.Fail(git_repo.FilePath(source), "filecontent", message, []string{}, MediumSeverity)
+	}
+}
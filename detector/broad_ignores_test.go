@@ -0,0 +1,73 @@
+package detector
+
+import (
+	"fmt"
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldFailAnIgnoreMatchingMoreFilesThanTheMaxFilesPerRule(t *testing.T) {
+	ignores := NewTalismanRCIgnore([]byte(`
+max_files_per_rule: 2
+fileignoreconfig:
+- filename: '*.go'
+  ignore_detectors: [filecontent]
+`)).WithSource(DefaultRCFileName)
+	results := NewDetectionResults()
+	ignores.failBroadIgnores(additionsNamed("main.go", "runner.go", "talisman.go", "README.md"), results)
+
+	assert.True(t, results.HasFailures(), "Expected an ignore matching 3 files to exceed the max of 2")
+	failures := results.GetFailures(git_repo.FilePath(DefaultRCFileName))
+	assert.Len(t, failures, 1)
+	assert.Contains(t, failures[0].Message, "Expected the ignore of *.go to match at most 2 files")
+	assert.Contains(t, failures[0].Message, "it matched 3 files such as: main.go, runner.go, talisman.go")
+}
+
+func TestShouldOnlyReportSomeOfTheFilesMatchedByABroadIgnore(t *testing.T) {
+	ignores := NewTalismanRCIgnore([]byte("max_files_per_rule: 1\nfileignoreconfig:\n- filename: '*'\n  ignore_detectors: [filename, filecontent]\n"))
+	var paths []string
+	for i := 0; i < 10; i++ {
+		paths = append(paths, fmt.Sprintf("file%d.txt", i))
+	}
+	results := NewDetectionResults()
+	ignores.failBroadIgnores(additionsNamed(paths...), results)
+
+	failures := results.GetFailures(git_repo.FilePath(DefaultRCFileName))
+	assert.Len(t, failures, 1)
+	assert.Contains(t, failures[0].Message, "it matched 10 files such as: file0.txt, file1.txt, file2.txt")
+	assert.NotContains(t, failures[0].Message, "file3.txt")
+}
+
+func TestShouldNotFailAPreciseIgnoreUnderTheMaxFilesPerRule(t *testing.T) {
+	ignores := NewTalismanRCIgnore([]byte(`
+max_files_per_rule: 2
+fileignoreconfig:
+- filename: test/fixtures/secret.pem
+  ignore_detectors: [filename]
+- filename: 'docs/*.md'
+  ignore_detectors: [filecontent]
+`))
+	results := NewDetectionResults()
+	ignores.failBroadIgnores(additionsNamed("test/fixtures/secret.pem", "docs/a.md", "docs/b.md", "main.go"), results)
+
+	assert.False(t, results.HasFailures(), "Expected ignores matching at most 2 files to be allowed")
+}
+
+func TestShouldNotLimitTheFilesPerRuleByDefault(t *testing.T) {
+	ignores := NewTalismanRCIgnore([]byte("fileignoreconfig:\n- filename: '*'\n  ignore_detectors: [filename, filecontent]\n"))
+	results := NewDetectionResults()
+	ignores.failBroadIgnores(additionsNamed("a.txt", "b.txt", "c.txt"), results)
+
+	assert.False(t, results.HasFailures())
+}
+
+func TestChainShouldFailBroadIgnoresEvenIfEveryFileIsIgnored(t *testing.T) {
+	ignores := NewTalismanRCIgnore([]byte("max_files_per_rule: 1\nfileignoreconfig:\n- filename: '*'\n  ignore_detectors: [filename, filecontent]\n"))
+	results := NewDetectionResults()
+	DefaultChain().Test(additionsNamed("a.txt", "b.txt"), ignores, results)
+
+	assert.True(t, results.HasFailures())
+}
@@ -1,6 +1,8 @@
 package detector
 
 import (
+	"time"
+
 	"talisman/git_repo"
 	"talisman/utility"
 )
@@ -16,18 +18,43 @@ func NewChecksumCompare(gitAdditions []git_repo.Addition, talismanRCIgnoreConfig
 	return &cc
 }
 
+//PrecomputeChecksums hashes the files that the checksums of the file ignores are calculated from with the given number of workers,
+//so that the detectors, which compare these checksums addition by addition, find the hashes already cached
+func (cc *ChecksumCompare) PrecomputeChecksums(workers int) {
+	var paths []string
+	for _, ignore := range cc.ignoreConfig.FileIgnoreConfig {
+		if ignore.Checksum == "" {
+			continue
+		}
+		paths = append(paths, ignore.filePath())
+		for _, addition := range cc.additions {
+			if ignore.Matches(addition) {
+				paths = append(paths, string(addition.Path))
+			}
+		}
+	}
+	utility.HashFilesInParallel(paths, workers)
+}
+
 func (cc *ChecksumCompare) IsScanNotRequired(addition git_repo.Addition) bool {
-	currentCollectiveChecksum := utility.CollectiveSHA256Hash([]string{string(addition.Path)})
+	_, matched := cc.checksumRule(addition)
+	return matched
+}
+
+//checksumRule returns the file ignore whose checksum makes the scan of the addition not required, which is the last one matching it
+func (cc *ChecksumCompare) checksumRule(addition git_repo.Addition) (FileIgnoreConfig, bool) {
+	currentCollectiveChecksum := utility.CollectiveChecksum([]string{string(addition.Path)})
 	declaredCheckSum := ""
+	var rule FileIgnoreConfig
 	for _, ignore := range cc.ignoreConfig.FileIgnoreConfig {
-		if addition.Matches(ignore.FileName) {
-			currentCollectiveChecksum = utility.CollectiveSHA256Hash([]string{ignore.FileName})
+		if ignore.Matches(addition) && !ignore.IsExpired(time.Now()) {
+			currentCollectiveChecksum = utility.CollectiveChecksum([]string{ignore.filePath()})
 			declaredCheckSum = ignore.Checksum
+			rule = ignore
 		}
 
 	}
-	return currentCollectiveChecksum == declaredCheckSum
-
+	return rule, currentCollectiveChecksum == declaredCheckSum
 }
 
 //FilterIgnoresBasedOnChecksums filters the file ignores from the TalismanRCIgnore which doesn't have any checksum value or having mismatched checksum value from the .talsimanrc
@@ -56,7 +83,7 @@ func (cc *ChecksumCompare) calculateCollectiveChecksumForPattern(fileNamePattern
 	// Calculate current collective checksum
 	patternpaths = utility.UniqueItems(patternpaths)
 	if len(patternpaths) != 0 {
-		currentCollectiveChecksum = utility.CollectiveSHA256Hash(patternpaths)
+		currentCollectiveChecksum = utility.CollectiveChecksum(patternpaths)
 	}
 	return currentCollectiveChecksum
 }
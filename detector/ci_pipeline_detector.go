@@ -0,0 +1,87 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const minCISecretLength = 8
+
+var ciPipelinePathPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(^|/)\.github/workflows/[^/]+\.ya?ml$`),
+	regexp.MustCompile(`(^|/)\.gitlab-ci\.ya?ml$`),
+	regexp.MustCompile(`(^|/)Jenkinsfile$`),
+}
+
+//ciAssignmentPattern matches YAML keys, Jenkinsfile environment entries and shell exports along with the value assigned to them
+var ciAssignmentPattern = regexp.MustCompile(`^\s*(?:-\s+)?(?:export\s+)?["']?([A-Za-z0-9_.-]+)["']?\s*[:=]\s*(.*?)\s*$`)
+
+var ciSecretNamePattern = regexp.MustCompile(`(?i)(token|secret|passw(or)?d|pwd|api[_-]?key|access[_-]?key|private[_-]?key|credential)`)
+
+//ciSecretReferencePattern matches the ways in which pipelines refer to secrets kept in a secret store or in CI variables
+var ciSecretReferencePattern = regexp.MustCompile(`\$\{\{[^}]*\}\}|\$\{[A-Za-z_][A-Za-z0-9_]*\}|\$[A-Za-z_][A-Za-z0-9_]*|credentials\([^)]*\)`)
+
+//CIPipelineDetector flags literal secrets in CI pipeline definitions, which are expected to refer to a secret store instead
+type CIPipelineDetector struct {
+	base64Detector *Base64Detector
+}
+
+//NewCIPipelineDetector returns a CIPipelineDetector that scans GitHub Actions workflows, GitLab CI definitions and Jenkinsfiles
+func NewCIPipelineDetector() *CIPipelineDetector {
+	return &CIPipelineDetector{NewBase64Detector()}
+}
+
+//Test tests the pipeline definitions among the Additions to ensure that they don't hardcode secrets
+func (cd *CIPipelineDetector) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	for _, addition := range additions {
+		if !isCIPipelineDefinition(addition) {
+			continue
+		}
+		if ignoreConfig.Deny(addition, "filecontent") || cc.IsScanNotRequired(addition) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Ignoring addition as it was specified to be ignored.")
+			result.Ignore(addition.Path, "filecontent")
+			continue
+		}
+		for _, line := range ignoreConfig.reportableFindings(CIPipelineDetectorName, addition.Path, cd.hardcodedSecrets(string(addition.Data)), result) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Failing file as it hardcodes a secret in a pipeline definition.")
+			result.FailAt(addition.Path, "filecontent", fmt.Sprintf("Expected pipeline definition to refer to a secret store instead of hardcoding secrets such as: %s", line), addition.Commits, HighSeverity, findingIn(addition.Data, line))
+		}
+	}
+}
+
+func isCIPipelineDefinition(addition git_repo.Addition) bool {
+	for _, pattern := range ciPipelinePathPatterns {
+		if pattern.MatchString(string(addition.Path)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (cd *CIPipelineDetector) hardcodedSecrets(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		match := ciAssignmentPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		literal := strings.TrimSpace(ciSecretReferencePattern.ReplaceAllString(strings.Trim(match[2], "\"'"), ""))
+		if len(literal) < minCISecretLength {
+			continue
+		}
+		if ciSecretNamePattern.MatchString(match[1]) || cd.base64Detector.checkBase64Encoding(literal) != "" {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	return lines
+}
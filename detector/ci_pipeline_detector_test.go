@@ -0,0 +1,73 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const workflowWithHardcodedToken = `name: deploy
+on: push
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    env:
+      DEPLOY_TOKEN: 9f8e7d6c5b4a39281706f5e4d3c2b1a0
+    steps:
+      - run: ./deploy.sh
+`
+
+const workflowWithSecretReference = `name: deploy
+on: push
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    env:
+      DEPLOY_TOKEN: ${{ secrets.DEPLOY_TOKEN }}
+      AUTH_HEADER: "Bearer ${{ secrets.API_TOKEN }}"
+    steps:
+      - run: ./deploy.sh --token $DEPLOY_TOKEN
+`
+
+func TestShouldFlagHardcodedTokenInWorkflow(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition(".github/workflows/deploy.yml", []byte(workflowWithHardcodedToken))}
+
+	NewCIPipelineDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.True(t, results.HasFailures(), "Expected a hardcoded token in a workflow to fail")
+	assert.Equal(t, "Expected pipeline definition to refer to a secret store instead of hardcoding secrets such as: DEPLOY_TOKEN: 9f8e7d6c5b4a39281706f5e4d3c2b1a0", getFailureMessages(results, additions[0].Path)[0])
+}
+
+func TestShouldNotFlagSecretReferencesInWorkflow(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition(".github/workflows/deploy.yml", []byte(workflowWithSecretReference))}
+
+	NewCIPipelineDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasFailures(), "Expected references to the secret store to pass")
+}
+
+func TestShouldFlagHardcodedPasswordInGitlabCIAndJenkinsfile(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{
+		git_repo.NewAddition(".gitlab-ci.yml", []byte("variables:\n  DB_PASSWORD: \"correct-horse-battery\"\n  DB_USER: $CI_DB_USER\n")),
+		git_repo.NewAddition("Jenkinsfile", []byte("environment {\n  API_KEY = 'a1b2c3d4e5f6g7h8'\n  DEPLOY = credentials('deploy-key')\n}\n")),
+	}
+
+	NewCIPipelineDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.Len(t, results.GetFailures(additions[0].Path), 1)
+	assert.Len(t, results.GetFailures(additions[1].Path), 1)
+}
+
+func TestShouldNotScanFilesThatAreNotPipelineDefinitions(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("docs/deploy.yml", []byte(workflowWithHardcodedToken))}
+
+	NewCIPipelineDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasFailures(), "Expected only pipeline definitions to be scanned")
+}
@@ -0,0 +1,53 @@
+package detector
+
+import (
+	"reflect"
+	"strings"
+)
+
+//JSONSchema represents the subset of JSON Schema used to describe the structure of the .talismanrc
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 string                 `json:"type"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+}
+
+//TalismanRCSchema returns the JSON Schema of the .talismanrc. It is generated from the yaml tags of TalismanRCIgnore, so that it stays in sync with the settings that are read.
+func TalismanRCSchema() *JSONSchema {
+	schema := schemaOf(reflect.TypeOf(TalismanRCIgnore{}))
+	schema.Schema = "http://json-schema.org/draft-07/schema#"
+	schema.Title = DefaultRCFileName
+	return schema
+}
+
+func schemaOf(t reflect.Type) *JSONSchema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaOf(t.Elem())
+	case reflect.Struct:
+		schema := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}, AdditionalProperties: false}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if field.PkgPath != "" || name == "" || name == "-" {
+				continue
+			}
+			schema.Properties[name] = schemaOf(field.Type)
+		}
+		return schema
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: schemaOf(t.Elem())}
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: "array", Items: schemaOf(t.Elem())}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	}
+	return &JSONSchema{Type: "string"}
+}
@@ -0,0 +1,107 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+const knownGoodConfig = `fileignoreconfig:
+- filename: fixtures/key.pem
+  checksum: abc
+  ignore_detectors: [filename]
+  expires: 2030-01-01
+scopeconfig:
+- scope: go
+max_line_length: 1000
+long_line_action: skip
+allowed_patterns:
+- EXAMPLE
+detectors:
+  pattern:
+    min_value_length: 8
+fixtures:
+  paths: [test/fixtures/]
+  failing_entropy: 5.5
+markdown_fences_only: true
+enforce: false
+`
+
+func TestSchemaShouldValidateAKnownGoodConfig(t *testing.T) {
+	assert.Empty(t, validateAgainstSchema(t, knownGoodConfig))
+}
+
+func TestSchemaShouldRejectUnknownKeys(t *testing.T) {
+	assert.Equal(t, []string{"fileignoreconfig[0]: unknown key ignore_detector"}, validateAgainstSchema(t, "fileignoreconfig:\n- filename: a.pem\n  ignore_detector: [filename]\n"))
+	assert.Equal(t, []string{": unknown key max_line_lenght"}, validateAgainstSchema(t, "max_line_lenght: 100\n"))
+}
+
+func TestSchemaShouldRejectValuesOfTheWrongType(t *testing.T) {
+	assert.Equal(t, []string{"max_line_length: expected integer"}, validateAgainstSchema(t, "max_line_length: long\n"))
+}
+
+//validateAgainstSchema validates the config against the emitted schema, read back from its JSON so that the output itself is tested
+func validateAgainstSchema(t *testing.T, config string) []string {
+	emitted, err := json.Marshal(TalismanRCSchema())
+	assert.NoError(t, err)
+	var schema map[string]interface{}
+	assert.NoError(t, json.Unmarshal(emitted, &schema))
+	var value interface{}
+	assert.NoError(t, yaml.Unmarshal([]byte(config), &value))
+	return validate(schema, value, "")
+}
+
+func validate(schema map[string]interface{}, value interface{}, path string) []string {
+	var errors []string
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return []string{path + ": expected object"}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for key, propertyValue := range object {
+			propertyPath := fmt.Sprintf("%s.%v", path, key)
+			if path == "" {
+				propertyPath = fmt.Sprint(key)
+			}
+			if property, ok := properties[fmt.Sprint(key)]; ok {
+				errors = append(errors, validate(property.(map[string]interface{}), propertyValue, propertyPath)...)
+			} else if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				errors = append(errors, validate(additional, propertyValue, propertyPath)...)
+			} else if schema["additionalProperties"] == false {
+				errors = append(errors, fmt.Sprintf("%s: unknown key %v", path, key))
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return []string{path + ": expected array"}
+		}
+		for i, item := range array {
+			errors = append(errors, validate(schema["items"].(map[string]interface{}), item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "integer":
+		if _, ok := value.(int); !ok {
+			errors = append(errors, path+": expected integer")
+		}
+	case "number":
+		switch value.(type) {
+		case int, float64:
+		default:
+			errors = append(errors, path+": expected number")
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			errors = append(errors, path+": expected boolean")
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			errors = append(errors, path+": expected string")
+		}
+	}
+	return errors
+}
@@ -0,0 +1,46 @@
+package detector
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"talisman/git_repo"
+)
+
+//ConfigSource supplies the contents of the config files, such as the .talismanrc and the legacy ignore file, by name.
+//Embedders can implement it to read the configs from an in-memory overlay or a remote store instead of the repository.
+//A source answers empty contents, and no error, for the config files it does not have, as they are all optional.
+type ConfigSource interface {
+	ReadConfig(fileName string) ([]byte, error)
+}
+
+//ConfigSourceFunc adapts a function reading files by name, such as GitRepo.ReadRepoFileOrNothing, to a ConfigSource
+type ConfigSourceFunc func(fileName string) ([]byte, error)
+
+//ReadConfig calls the function with the file name
+func (f ConfigSourceFunc) ReadConfig(fileName string) ([]byte, error) {
+	return f(fileName)
+}
+
+//GitConfigSource reads the config files from the working tree of the git repository, relative to its root
+func GitConfigSource(repo git_repo.GitRepo) ConfigSource {
+	return ConfigSourceFunc(repo.ReadRepoFileOrNothing)
+}
+
+//FileSystemConfigSource reads the config files relative to the Root directory. Absolute file names are read as they are.
+type FileSystemConfigSource struct {
+	Root string
+}
+
+//ReadConfig returns the contents of the file, or empty contents if there is no such file
+func (s FileSystemConfigSource) ReadConfig(fileName string) ([]byte, error) {
+	if !filepath.IsAbs(fileName) {
+		fileName = filepath.Join(s.Root, fileName)
+	}
+	contents, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return []byte{}, nil
+	}
+	return contents, err
+}
@@ -0,0 +1,60 @@
+package detector
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//inMemoryConfigSource is the kind of source an embedder would supply, holding the configs of an overlay in memory
+type inMemoryConfigSource map[string]string
+
+func (s inMemoryConfigSource) ReadConfig(fileName string) ([]byte, error) {
+	return []byte(s[fileName]), nil
+}
+
+func TestShouldReadTheConfigsFromACustomSource(t *testing.T) {
+	source := inMemoryConfigSource{
+		DefaultRCFileName:     "fileignoreconfig:\n- filename: overlay.pem\n  ignore_detectors: [filename]\n",
+		"org.rc":              "max_line_length: 1000\n",
+		DefaultIgnoreFileName: "fixtures/\n",
+	}
+
+	rc := ReadConfigFromRCFile(source)
+	chain := ReadConfigChain(source, []string{"org.rc", DefaultRCFileName})
+	legacy := ReadIgnoresFromFile(source, "")
+
+	assert.True(t, rc.Deny(testAddition("overlay.pem"), "filename"), "Expected the .talismanrc of the source to be read")
+	assert.Equal(t, 1000, chain.MaxLineLength)
+	assert.True(t, chain.Deny(testAddition("overlay.pem"), "filename"), "Expected every config of the chain to be read from the source")
+	assert.True(t, legacy.Deny(testAddition("fixtures/data.json"), "filecontent"), "Expected the legacy ignores of the source to be read")
+}
+
+func TestFileSystemConfigSourceShouldReadRelativeToItsRoot(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "talisman-config-source")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, DefaultRCFileName), []byte("max_line_length: 42\n"), 0644)
+	source := FileSystemConfigSource{Root: dir}
+
+	assert.Equal(t, 42, ReadConfigFromRCFile(source).MaxLineLength)
+	contents, err := source.ReadConfig(filepath.Join(dir, DefaultRCFileName))
+	assert.NoError(t, err)
+	assert.Equal(t, "max_line_length: 42\n", string(contents), "Expected absolute file names to be read as they are")
+}
+
+func TestConfigSourcesShouldAnswerNothingForMissingFiles(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "talisman-config-source")
+	defer os.RemoveAll(dir)
+
+	for _, source := range []ConfigSource{FileSystemConfigSource{Root: dir}, GitConfigSource(git_repo.RepoLocatedAt(dir))} {
+		contents, err := source.ReadConfig(DefaultRCFileName)
+		assert.NoError(t, err)
+		assert.Empty(t, contents)
+		assert.True(t, ReadConfigFromRCFile(source).IsEmpty())
+	}
+}
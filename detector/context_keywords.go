@@ -0,0 +1,85 @@
+package detector
+
+import (
+	"strings"
+)
+
+//DefaultContextWindow is the number of lines around a finding that are searched for a keyword when the context detector does not set a window
+const DefaultContextWindow = 3
+
+//defaultContextKeywords are the keywords that suggest a secret is nearby when the context detector does not list its own
+var defaultContextKeywords = []string{"password", "passwd", "pwd", "secret", "token", "key", "credential", "auth"}
+
+//ContextDetectorConfig represents the opt-in mode in which the high entropy findings of the content detectors are only reported
+//when one of the Keywords appears within Window lines of them. This trades some recall for much fewer false positives.
+type ContextDetectorConfig struct {
+	Enabled  bool     `yaml:"enabled,omitempty"`
+	Keywords []string `yaml:"keywords,omitempty"`
+	Window   int      `yaml:"window,omitempty"`
+}
+
+func (c ContextDetectorConfig) mergeWith(other ContextDetectorConfig) ContextDetectorConfig {
+	result := ContextDetectorConfig{Enabled: c.Enabled || other.Enabled, Window: c.Window}
+	result.Keywords = append(append(result.Keywords, c.Keywords...), other.Keywords...)
+	if other.Window != 0 {
+		result.Window = other.Window
+	}
+	return result
+}
+
+func (c ContextDetectorConfig) keywords() []string {
+	if len(c.Keywords) > 0 {
+		return c.Keywords
+	}
+	return defaultContextKeywords
+}
+
+func (c ContextDetectorConfig) window() int {
+	if c.Window > 0 {
+		return c.Window
+	}
+	return DefaultContextWindow
+}
+
+//findingsInContext returns the findings with a keyword on their line or within the window of lines around them, or all the findings unless Enabled.
+//The text of a finding is left out of its own line, so that a random looking value does not provide its own context.
+func (c ContextDetectorConfig) findingsInContext(data []byte, findings []string) []string {
+	if !c.Enabled {
+		return findings
+	}
+	lines := strings.Split(strings.ToLower(string(data)), "\n")
+	var result []string
+	for _, text := range findings {
+		finding := findingIn(data, text)
+		if finding.Line == 0 {
+			continue
+		}
+		first, last := finding.Line-1-c.window(), finding.Line-1+c.window()
+		if first < 0 {
+			first = 0
+		}
+		if last > len(lines)-1 {
+			last = len(lines) - 1
+		}
+		for i := first; i <= last; i++ {
+			line := lines[i]
+			if i == finding.Line-1 {
+				line = strings.Replace(line, strings.ToLower(text), "", 1)
+			}
+			if containsKeyword(line, c.keywords()) {
+				result = append(result, text)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func containsKeyword(line string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if keyword != "" && strings.Contains(line, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
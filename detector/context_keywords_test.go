@@ -0,0 +1,57 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const contextEntropyHit = "ZGF0YWJhc2UtcGFzc3dvcmQtZm9yLXByb2R1Y3Rpb24tMjAxOQ=="
+
+func TestContextDetectorShouldReportEntropyHitsNearAKeyword(t *testing.T) {
+	results := NewDetectionResults()
+	content := "database:\n  host: db.internal\n  password = " + contextEntropyHit + "\n"
+	additions := []git_repo.Addition{git_repo.NewAddition("config.txt", []byte(content))}
+
+	NewFileContentDetector().Test(additions, TalismanRCIgnore{ContextDetector: ContextDetectorConfig{Enabled: true}}, results)
+
+	assert.Len(t, results.GetFailures("config.txt"), 1, "Expected the entropy hit next to password to be reported")
+}
+
+func TestContextDetectorShouldSuppressIsolatedEntropyHits(t *testing.T) {
+	results := NewDetectionResults()
+	content := "release notes\n\n\n\n\nchecksum of the build: " + contextEntropyHit + "\n"
+	additions := []git_repo.Addition{git_repo.NewAddition("notes.txt", []byte(content))}
+
+	NewFileContentDetector().Test(additions, TalismanRCIgnore{ContextDetector: ContextDetectorConfig{Enabled: true}}, results)
+	assert.False(t, results.HasFailures(), "Expected the isolated entropy hit to be suppressed")
+
+	results = NewDetectionResults()
+	NewFileContentDetector().Test(additions, TalismanRCIgnore{}, results)
+	assert.True(t, results.HasFailures(), "Expected the isolated entropy hit to be reported without the context detector")
+}
+
+func TestContextDetectorShouldUseTheConfiguredKeywordsAndWindow(t *testing.T) {
+	context := ContextDetectorConfig{Enabled: true, Keywords: []string{"Signing"}, Window: 1}
+	content := []byte("signing material:\nfirst\n" + contextEntropyHit + "\n")
+
+	assert.Empty(t, context.findingsInContext(content, []string{contextEntropyHit}), "Expected the keyword outside of the window to be ignored")
+
+	context.Window = 2
+	assert.Equal(t, []string{contextEntropyHit}, context.findingsInContext(content, []string{contextEntropyHit}))
+}
+
+func TestContextDetectorShouldNotTakeTheFindingAsItsOwnContext(t *testing.T) {
+	context := ContextDetectorConfig{Enabled: true, Keywords: []string{"c2VjcmV0"}}
+
+	assert.Empty(t, context.findingsInContext([]byte("c2VjcmV0LXZhbHVl\n"), []string{"c2VjcmV0LXZhbHVl"}))
+}
+
+func TestContextDetectorConfigShouldBeMerged(t *testing.T) {
+	base := NewTalismanRCIgnore([]byte("context_detector:\n  keywords: [password]\n  window: 5\n"))
+	override := NewTalismanRCIgnore([]byte("context_detector:\n  enabled: true\n  keywords: [apikey]\n"))
+
+	assert.Equal(t, ContextDetectorConfig{Enabled: true, Keywords: []string{"password", "apikey"}, Window: 5}, base.MergeWith(override).ContextDetector)
+}
@@ -0,0 +1,31 @@
+package detector
+
+import (
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/bmatcuk/doublestar"
+)
+
+//isCriticalPath answers true if the file matches one of the critical_paths of the config, glob patterns in which ** spans directories
+func isCriticalPath(criticalPaths []string, filePath git_repo.FilePath) bool {
+	for _, pattern := range criticalPaths {
+		if matched, _ := doublestar.Match(pattern, string(filePath)); matched {
+			return true
+		}
+	}
+	return false
+}
+
+//severityOf returns the severity a finding in the file is reported with, which is critical for the files on a critical path whatever the detector said.
+//The severity actions apply to the elevated severity.
+func (r *DetectionResults) severityOf(filePath git_repo.FilePath, severity Severity) Severity {
+	if severity == CriticalSeverity || !isCriticalPath(r.criticalPaths, filePath) {
+		return severity
+	}
+	log.WithFields(log.Fields{
+		"filePath": filePath,
+		"severity": severity,
+	}).Info("Elevating finding to critical as the file is on a critical path.")
+	return CriticalSeverity
+}
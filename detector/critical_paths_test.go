@@ -0,0 +1,46 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindingsInACriticalPathShouldBeElevatedToCritical(t *testing.T) {
+	results := NewDetectionResults()
+	ignores := NewTalismanRCIgnore([]byte("critical_paths:\n- infra/secrets/**\n"))
+
+	NewChain().AddDetector(NewFileSizeDetector(4)).Test([]git_repo.Addition{
+		git_repo.NewAddition("infra/secrets/prod/db.env", []byte("a large file")),
+		git_repo.NewAddition("infra/db.env", []byte("a large file")),
+	}, ignores, results)
+
+	assert.Equal(t, CriticalSeverity, results.GetFailures("infra/secrets/prod/db.env")[0].Severity, "Expected the low finding in the critical path to be elevated")
+	assert.Equal(t, LowSeverity, results.GetFailures("infra/db.env")[0].Severity, "Expected the same finding elsewhere to keep its severity")
+}
+
+func TestCriticalPathsShouldBeElevatedBeforeTheSeverityActionsApply(t *testing.T) {
+	results := NewDetectionResults()
+	ignores := NewTalismanRCIgnore([]byte("critical_paths:\n- infra/secrets/**\nseverity_actions:\n  low: ignore\n  critical: fail\n"))
+
+	NewChain().AddDetector(NewFileSizeDetector(4)).Test([]git_repo.Addition{
+		git_repo.NewAddition("infra/secrets/prod/db.env", []byte("a large file")),
+		git_repo.NewAddition("infra/db.env", []byte("a large file")),
+	}, ignores, results)
+
+	assert.Len(t, results.GetFailures("infra/secrets/prod/db.env"), 1, "Expected the elevated finding to fail")
+	assert.Len(t, results.GetFailures("infra/db.env"), 0, "Expected the low finding elsewhere to be ignored")
+}
+
+func TestCriticalPathsOfAllTheConfigsShouldApply(t *testing.T) {
+	base := NewTalismanRCIgnore([]byte("critical_paths:\n- infra/secrets/**\n"))
+	override := NewTalismanRCIgnore([]byte("critical_paths:\n- '**/*.tfvars'\n"))
+
+	merged := base.MergeWith(override)
+
+	assert.Equal(t, []string{"infra/secrets/**", "**/*.tfvars"}, merged.CriticalPaths)
+	assert.True(t, isCriticalPath(merged.CriticalPaths, "envs/prod/main.tfvars"))
+	assert.False(t, isCriticalPath(merged.CriticalPaths, "infra/main.tf"))
+}
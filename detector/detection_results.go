@@ -2,12 +2,11 @@ package detector
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"talisman/git_repo"
 	"talisman/utility"
 
-	"github.com/olekukonko/tablewriter"
+	log "github.com/Sirupsen/logrus"
 	"gopkg.in/yaml.v2"
 )
 
@@ -15,6 +14,19 @@ type Details struct {
 	Category string `json:"type"`
 	Message string `json:"message"`
 	Commits []string `json:"commits"`
+	Severity Severity `json:"severity,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	EndLine  int      `json:"end_line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Secret   string   `json:"-"`
+}
+
+//SecretValue returns the value of the secret matched by the detector, without the key it was assigned to
+func (d Details) SecretValue() string {
+	if d.Secret == "" {
+		return ""
+	}
+	return assignedValue(d.Secret)
 }
 
 type ResultsDetails struct {
@@ -35,6 +47,14 @@ type FailureTypes struct  {
 type ResultsSummary struct {
 	Types FailureTypes `json:"types"`
 }
+
+//SampleSummary labels the results of a scan that only ran the detectors on a random sample of the history
+type SampleSummary struct {
+	Rate    float64 `json:"rate"`
+	Seed    int64   `json:"seed"`
+	Scanned int     `json:"scanned"`
+	Total   int     `json:"total"`
+}
 //
 //
 //type FailureData struct {
@@ -48,6 +68,16 @@ type ResultsSummary struct {
 type DetectionResults struct {
 	Summary ResultsSummary `json:"summary"`
 	Results []ResultsDetails `json:"results"`
+	Sample *SampleSummary `json:"sample,omitempty"`
+	fixtures FixtureConfig
+	suppressRules []SuppressRule
+	allowList *AllowList
+	severityActions SeverityActions
+	criticalPaths []string
+	audit *auditLog
+	currentDetector string
+	executedDetectors []string
+	stats ScanStats
 }
 
 func (r *ResultsDetails) getWarningDataByCategoryAndMessage(failureMessage string, category string) *Details {
@@ -59,7 +89,7 @@ func (r *ResultsDetails) getWarningDataByCategoryAndMessage(failureMessage strin
 func (r *ResultsDetails) getFailureDataByCategoryAndMessage(failureMessage string, category string) *Details {
 	detail := getDetaisByCategoryAndMessage(r.FailureList, category, failureMessage)
 	if detail == nil {
-		detail = &Details{category, failureMessage, make([]string, 0)}
+		detail = &Details{Category: category, Message: failureMessage, Commits: make([]string, 0)}
 		r.FailureList = append(r.FailureList, *detail)
 	}
 	return detail
@@ -73,7 +103,7 @@ func (r *ResultsDetails) addIgnoreDataByCategory(category string) {
 		}
 	}
 	if !isCategoryAlreadyPresent {
-		detail := Details{category, "", make([]string, 0)}
+		detail := Details{Category: category, Message: "", Commits: make([]string, 0)}
 		r.IgnoreList = append(r.IgnoreList, detail)
 	}
 }
@@ -101,7 +131,7 @@ func (r *DetectionResults) getResultDetailsForFilePath(fileName git_repo.FilePat
 
 //NewDetectionResults is a new DetectionResults struct. It represents the pre-run state of a Detection run.
 func NewDetectionResults() *DetectionResults {
-	result := DetectionResults{ResultsSummary{FailureTypes{0,0,0, 0, 0}},make([]ResultsDetails, 0)}
+	result := DetectionResults{Summary: ResultsSummary{FailureTypes{0,0,0, 0, 0}}, Results: make([]ResultsDetails, 0)}
 	return &result
 }
 
@@ -109,7 +139,28 @@ func NewDetectionResults() *DetectionResults {
 //Fail is used to mark the supplied FilePath as failing a detection for a supplied reason.
 //Detectors are encouraged to provide context sensitive messages so that fixing the errors is made simple for the end user
 //Fail may be called multiple times for each FilePath and the calls accumulate the provided reasons
-func (r *DetectionResults) Fail(filePath git_repo.FilePath, category string, message string, commits []string) {
+//The severity records how damaging the detection is likely to be, and is used to group and gate the results
+func (r *DetectionResults) Fail(filePath git_repo.FilePath, category string, message string, commits []string, severity Severity) {
+	r.FailAt(filePath, category, message, commits, severity, Finding{})
+}
+
+//FailAt is like Fail, but also records the text matched by the detector and its position within the file
+func (r *DetectionResults) FailAt(filePath git_repo.FilePath, category string, message string, commits []string, severity Severity, finding Finding) {
+	severity = r.severityOf(filePath, severity)
+	if r.isSuppressed(filePath, category, message, severity, finding) {
+		return
+	}
+	if r.isActedUpon(filePath, category, message, commits, severity, finding) {
+		return
+	}
+	if r.fixtures.isDowngraded(filePath, severity, finding) {
+		log.WithFields(log.Fields{
+			"filePath": filePath,
+		}).Info("Warning file instead of failing it as it is a test fixture.")
+		r.WarnAt(filePath, category, message, commits, severity, finding)
+		return
+	}
+	r.auditFinding(AuditReported, FailAction, filePath, category, message, severity, finding, "", "")
 	isFilePresentInResults := false
 	for resultIndex := 0; resultIndex < len(r.Results); resultIndex++ {
 		if r.Results[resultIndex].Filename == filePath {
@@ -122,12 +173,12 @@ func (r *DetectionResults) Fail(filePath git_repo.FilePath, category string, mes
 				}
 			}
 			if !isEntryPresentForGivenCategoryAndMessage {
-				r.Results[resultIndex].FailureList = append(r.Results[resultIndex].FailureList, Details{category, message, commits})
+				r.Results[resultIndex].FailureList = append(r.Results[resultIndex].FailureList, Details{category, message, commits, severity, finding.Line, finding.EndLine, finding.Column, finding.Text})
 			}
 		}
 	}
 	if !isFilePresentInResults {
-		failureDetails := Details{category, message, commits}
+		failureDetails := Details{category, message, commits, severity, finding.Line, finding.EndLine, finding.Column, finding.Text}
 		resultDetails := ResultsDetails{filePath, make([]Details, 0), make([]Details, 0), make([]Details, 0)}
 		resultDetails.FailureList = append(resultDetails.FailureList, failureDetails)
 		r.Results = append(r.Results, resultDetails)
@@ -135,7 +186,18 @@ func (r *DetectionResults) Fail(filePath git_repo.FilePath, category string, mes
 	r.updateResultsSummary(category)
 }
 
-func (r *DetectionResults) Warn(filePath git_repo.FilePath, category string, message string, commits []string) {
+//Warn is used to mark the supplied FilePath as containing content that should be reviewed, without failing the run
+func (r *DetectionResults) Warn(filePath git_repo.FilePath, category string, message string, commits []string, severity Severity) {
+	r.WarnAt(filePath, category, message, commits, severity, Finding{})
+}
+
+//WarnAt is like Warn, but also records the text matched by the detector and its position within the file
+func (r *DetectionResults) WarnAt(filePath git_repo.FilePath, category string, message string, commits []string, severity Severity, finding Finding) {
+	severity = r.severityOf(filePath, severity)
+	if r.isSuppressed(filePath, category, message, severity, finding) {
+		return
+	}
+	r.auditFinding(AuditReported, WarnAction, filePath, category, message, severity, finding, "", "")
 	isFilePresentInResults := false
 	for resultIndex := 0; resultIndex < len(r.Results); resultIndex++ {
 		if r.Results[resultIndex].Filename == filePath {
@@ -148,12 +210,12 @@ func (r *DetectionResults) Warn(filePath git_repo.FilePath, category string, mes
 				}
 			}
 			if !isEntryPresentForGivenCategoryAndMessage {
-				r.Results[resultIndex].WarningList = append(r.Results[resultIndex].WarningList, Details{category, message, commits})
+				r.Results[resultIndex].WarningList = append(r.Results[resultIndex].WarningList, Details{category, message, commits, severity, finding.Line, finding.EndLine, finding.Column, finding.Text})
 			}
 		}
 	}
 	if !isFilePresentInResults {
-		warningDetails := Details{category, message, commits}
+		warningDetails := Details{category, message, commits, severity, finding.Line, finding.EndLine, finding.Column, finding.Text}
 		resultDetails := ResultsDetails{filePath, make([]Details, 0), make([]Details, 0), make([]Details, 0)}
 		resultDetails.WarningList = append(resultDetails.WarningList, warningDetails)
 		r.Results = append(r.Results, resultDetails)
@@ -161,6 +223,23 @@ func (r *DetectionResults) Warn(filePath git_repo.FilePath, category string, mes
 	r.Summary.Types.Warnings++
 }
 
+//isSuppressed answers true if the finding is matched by a suppress expression, in which case the file is marked as ignored for the category instead.
+//Malformed suppress expressions are left out of the results, so they suppress nothing.
+func (r *DetectionResults) isSuppressed(filePath git_repo.FilePath, category string, message string, severity Severity, finding Finding) bool {
+	for _, rule := range r.suppressRules {
+		if rule.matches(r.currentDetector, filePath, category, message, severity, finding) {
+			log.WithFields(log.Fields{
+				"filePath":   filePath,
+				"expression": rule.Expression,
+			}).Info("Ignoring finding as it is matched by a suppress expression.")
+			r.auditFinding(AuditSuppressed, "", filePath, category, message, severity, finding, "suppress", rule.Expression)
+			r.Ignore(filePath, category)
+			return true
+		}
+	}
+	return false
+}
+
 //Ignore is used to mark the supplied FilePath as being ignored.
 //The most common reason for this is that the FilePath is Denied by the Ignores supplied to the Detector, however, Detectors may use more sophisticated reasons to ignore files.
 func (r *DetectionResults) Ignore(filePath git_repo.FilePath, category string) {
@@ -177,13 +256,13 @@ func (r *DetectionResults) Ignore(filePath git_repo.FilePath, category string) {
 				}
 			}
 			if !isEntryPresentForGivenCategory {
-				detail := Details{category, "", make([]string, 0)}
+				detail := Details{Category: category, Message: "", Commits: make([]string, 0)}
 				r.Results[resultIndex].IgnoreList = append(r.Results[resultIndex].IgnoreList, detail)
 			}
 		}
 	}
 	if !isFilePresentInResults {
-		ignoreDetails := Details{category, "", make([]string, 0)}
+		ignoreDetails := Details{Category: category, Message: "", Commits: make([]string, 0)}
 		resultDetails := ResultsDetails{filePath, make([]Details, 0), make([]Details, 0), make([]Details, 0)}
 		resultDetails.IgnoreList = append(resultDetails.IgnoreList, ignoreDetails)
 		r.Results = append(r.Results, resultDetails)
@@ -193,7 +272,7 @@ func (r *DetectionResults) Ignore(filePath git_repo.FilePath, category string) {
 
 
 func createNewResultForFile(category string, message string, commits []string, filePath git_repo.FilePath) ResultsDetails {
-	failureDetails := Details{category, message, commits}
+	failureDetails := Details{Category: category, Message: message, Commits: commits}
 	resultDetails := ResultsDetails{filePath, make([]Details, 0), make([]Details, 0), make([]Details, 0)}
 	resultDetails.FailureList = append(resultDetails.FailureList, failureDetails)
 	return resultDetails
@@ -210,6 +289,16 @@ func (r *DetectionResults) updateResultsSummary(category string) {
 
 }
 
+//ExecutedDetectors returns the names of the detectors that scanned the additions of the current run, in the order they ran
+func (r *DetectionResults) ExecutedDetectors() []string {
+	return r.executedDetectors
+}
+
+//Stats returns the stats of the current run, which the caller can add the files it skipped before the run to
+func (r *DetectionResults) Stats() *ScanStats {
+	return &r.stats
+}
+
 //HasFailures answers if any Failures were detected for any FilePath in the current run
 func (r *DetectionResults) HasFailures() bool {
 	return r.Summary.Types.Filesize > 0 || r.Summary.Types.Filename > 0 || r.Summary.Types.Filecontent > 0
@@ -242,59 +331,44 @@ func (r *DetectionResults) GetFailures(fileName git_repo.FilePath) []Details {
 	return results.FailureList
 }
 
+//ReportWarnings returns a string documenting the various warnings for the current run, grouped by file
 func (r *DetectionResults) ReportWarnings() string {
-	var result string
-	var filePathsForWarnings []string
-	var data [][]string
-
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"File", "Warnings"})
-	table.SetRowLine(true)
-
-	for _, resultDetails := range r.Results {
-		if len(resultDetails.WarningList) > 0 {
-			filePathsForWarnings = append(filePathsForWarnings, string(resultDetails.Filename))
-			warningData := r.ReportFileWarnings(resultDetails.Filename)
-			data = append(data, warningData...)
-		}
-	}
-
+	return r.ReportWarningsGroupedBy(GroupByFile)
+}
 
-	filePathsForWarnings = utility.UniqueItems(filePathsForWarnings)
+//ReportWarningsGroupedBy returns a string documenting the various warnings for the current run, organized by the given grouping
+func (r *DetectionResults) ReportWarningsGroupedBy(groupBy GroupBy) string {
+	var result string
 	if r.Summary.Types.Warnings > 0 {
-		fmt.Printf("\n\x1b[1m\x1b[31mTalisman Warnings:\x1b[0m\x1b[0m\n")
-		table.AppendBulk(data)
-		table.Render()
+		result = result + fmt.Sprintf("\n\x1b[1m\x1b[31mTalisman Warnings:\x1b[0m\x1b[0m\n")
+		result = result + renderGroups(r.groupResults(groupBy, false, true), groupBy, "Warnings", warningList)
 		result = result + fmt.Sprintf("\n\x1b[33mPlease review the above file(s) to make sure that no sensitive content is being pushed\x1b[0m\n")
 		result = result + fmt.Sprintf("\n")
 	}
 	return result
 }
 
-//Report returns a string documenting the various failures and ignored files for the current run
+//Report returns a string documenting the various failures and ignored files for the current run, grouped by file
 func (r *DetectionResults) Report() string {
+	return r.ReportGroupedBy(GroupByFile)
+}
+
+//ReportGroupedBy returns a string documenting the various failures and ignored files for the current run, organized by the given grouping
+func (r *DetectionResults) ReportGroupedBy(groupBy GroupBy) string {
 	var result string
 	var filePathsForIgnoresAndFailures []string
-	var data [][]string
-
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"File", "Errors"})
-	table.SetRowLine(true)
 
-	for _, resultDetails := range r.Results {
+	for _, resultDetails := range r.sortedResults() {
 		if len(resultDetails.FailureList) > 0 || len(resultDetails.IgnoreList) > 0 {
 			filePathsForIgnoresAndFailures = append(filePathsForIgnoresAndFailures, string(resultDetails.Filename))
-			failureData := r.ReportFileFailures(resultDetails.Filename)
-			data = append(data, failureData...)
 		}
 	}
 
 	filePathsForIgnoresAndFailures = utility.UniqueItems(filePathsForIgnoresAndFailures)
 
 	if r.HasFailures() {
-		fmt.Printf("\n\x1b[1m\x1b[31mTalisman Report:\x1b[0m\x1b[0m\n")
-		table.AppendBulk(data)
-		table.Render()
+		result = result + fmt.Sprintf("\n\x1b[1m\x1b[31mTalisman Report:\x1b[0m\x1b[0m\n")
+		result = result + renderGroups(r.groupResults(groupBy, true, false), groupBy, "Errors", failureList)
 		result = result + fmt.Sprintf("\n\x1b[33mIf you are absolutely sure that you want to ignore the above files from talisman detectors, consider pasting the following format in .talismanrc file in the project root\x1b[0m\n")
 		result = result + r.suggestTalismanRC(filePathsForIgnoresAndFailures)
 		result = result + fmt.Sprintf("\n\n")
@@ -302,11 +376,19 @@ func (r *DetectionResults) Report() string {
 	return result
 }
 
+func failureList(resultDetails ResultsDetails) []Details {
+	return resultDetails.FailureList
+}
+
+func warningList(resultDetails ResultsDetails) []Details {
+	return resultDetails.WarningList
+}
+
 func (r *DetectionResults) suggestTalismanRC(filePaths []string) string {
 	var fileIgnoreConfigs []FileIgnoreConfig
 	for _, filePath := range filePaths {
-		currentChecksum := utility.CollectiveSHA256Hash([]string{filePath})
-		fileIgnoreConfig := FileIgnoreConfig{filePath, currentChecksum, []string{}}
+		currentChecksum := utility.CollectiveChecksum([]string{filePath})
+		fileIgnoreConfig := FileIgnoreConfig{FileName: filePath, Checksum: currentChecksum, IgnoreDetectors: []string{}}
 		fileIgnoreConfigs = append(fileIgnoreConfigs, fileIgnoreConfig)
 	}
 
@@ -1,8 +1,11 @@
 package detector
 
 import (
-	"os"
+	"time"
+
 	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
 )
 
 //Detector represents a single kind of test to be performed against a set of Additions
@@ -15,39 +18,181 @@ type Detector interface {
 //Chain represents a chain of Detectors.
 //It is itself a detector.
 type Chain struct {
-	detectors []Detector
+	detectors []chainedDetector
+}
+
+//chainedDetector is a detector of the chain, along with the name it is reported by and the category of the ignores it honors
+type chainedDetector struct {
+	name     string
+	category string
+	detector Detector
+}
+
+//optInDetector is implemented by the detectors that only run when they are enabled in the ignoreConfig.
+//The chain checks isEnabled before calling Test, so such a detector does not guard itself
+type optInDetector interface {
+	isEnabled(ignoreConfig TalismanRCIgnore) bool
 }
 
 //NewChain returns an empty DetectorChain
 //It is itself a detector, but it tests nothing.
 func NewChain() *Chain {
-	result := Chain{make([]Detector, 0)}
+	result := Chain{make([]chainedDetector, 0)}
 	return &result
 }
 
 //DefaultChain returns a DetectorChain with pre-configured detectors
 func DefaultChain() *Chain {
 	result := NewChain()
-	result.AddDetector(DefaultFileNameDetector())
-	result.AddDetector(NewFileContentDetector())
-	result.AddDetector(NewPatternDetector())
+	result.AddNamedDetector(FileNameDetectorName, "filename", DefaultFileNameDetector())
+	result.AddNamedDetector(FileContentDetectorName, "filecontent", NewFileContentDetector())
+	result.AddNamedDetector(PatternDetectorName, "filecontent", NewPatternDetector())
+	result.AddNamedDetector(RegistryTokenDetectorName, "filecontent", NewRegistryTokenDetector())
+	result.AddNamedDetector(NetrcDetectorName, "filecontent", NewNetrcDetector())
+	result.AddNamedDetector(ShellHistoryDetectorName, "filecontent", NewShellHistoryDetector())
+	result.AddNamedDetector(KnownTokenDetectorName, "filecontent", NewKnownTokenDetector())
+	result.AddNamedDetector(CIPipelineDetectorName, "filecontent", NewCIPipelineDetector())
+	result.AddNamedDetector(AnsibleVaultDetectorName, "filecontent", NewAnsibleVaultDetector())
+	result.AddNamedDetector(LogStatementDetectorName, "filecontent", NewLogStatementDetector())
+	result.AddNamedDetector(EnvDumpDetectorName, "filecontent", NewEnvDumpDetector())
+	result.AddNamedDetector(WeakCredentialDetectorName, "filecontent", NewWeakCredentialDetector())
+	result.AddNamedDetector(OAuthTokenDetectorName, "filecontent", NewOAuthTokenDetector())
+	result.AddNamedDetector(HTTPFixtureDetectorName, "filecontent", NewHTTPFixtureDetector())
+	result.AddNamedDetector(ProtoDetectorName, "filecontent", NewProtoDetector())
+	result.AddNamedDetector(KeyPathDetectorName, "filecontent", NewKeyPathDetector())
+	result.AddNamedDetector(SuppressedSecretDetectorName, "filecontent", NewSuppressedSecretDetector())
+	result.AddNamedDetector(MetadataFileDetectorName, "filename", NewMetadataFileDetector())
 	return result
 }
 
 //AddDetector adds the detector that is passed in to the chain
 func (dc *Chain) AddDetector(d Detector) *Chain {
-	dc.detectors = append(dc.detectors, d)
+	dc.detectors = append(dc.detectors, chainedDetector{detector: d})
+	return dc
+}
+
+//AddNamedDetector adds the detector that is passed in to the chain, recording it as executed under the given name
+//whenever it gets to scan an addition that is not ignored for the given category
+func (dc *Chain) AddNamedDetector(name string, category string, d Detector) *Chain {
+	dc.detectors = append(dc.detectors, chainedDetector{name, category, d})
 	return dc
 }
 
-//Test validates the additions against each detector in the chain.
+//DetectorNames returns the names of the named detectors of the chain, in the order they are run
+func (dc *Chain) DetectorNames() []string {
+	var names []string
+	for _, d := range dc.detectors {
+		if d.name != "" {
+			names = append(names, d.name)
+		}
+	}
+	return names
+}
+
+//scansAnyAddition answers true if there is nothing to scan, or if one of the additions is not ignored for the category.
+//A detector for which every addition is ignored has not executed, as is the case when a broken config ignores all files.
+func scansAnyAddition(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, cc *ChecksumCompare, category string) bool {
+	if len(additions) == 0 {
+		return true
+	}
+	for _, addition := range additions {
+		if !ignoreConfig.Deny(addition, category) && !cc.IsScanNotRequired(addition) {
+			return true
+		}
+	}
+	return false
+}
+
+//recordScannedAdditions counts the additions that are scanned, along with their bytes, and the reason the others are skipped for
+func recordScannedAdditions(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, cc *ChecksumCompare, stats *ScanStats) {
+	for _, addition := range additions {
+		if ignoreConfig.Deny(addition, "filename") && ignoreConfig.Deny(addition, "filecontent") {
+			stats.Skip(SkippedIgnored, 1)
+		} else if cc.IsScanNotRequired(addition) {
+			stats.Skip(SkippedChecksum, 1)
+		} else {
+			stats.FilesScanned++
+			stats.BytesProcessed += int64(len(addition.Data))
+		}
+	}
+}
+
+//contentToScan returns the additions as the content detectors scan them, with only the code fences of the Markdown files
+//when markdown_fences_only is set, and with the lines longer than the max_line_length limited.
+//The long lines are only warned about once, and only in the additions that get scanned.
+func (i TalismanRCIgnore) contentToScan(additions []git_repo.Addition, longLineAction string, cc *ChecksumCompare, result *DetectionResults) []git_repo.Addition {
+	var content []git_repo.Addition
+	for _, addition := range additions {
+		addition.Data = i.markdownCodeFences(addition)
+		if !i.Deny(addition, "filecontent") && !cc.IsScanNotRequired(addition) {
+			addition.Data = i.limitLineLength(addition, longLineAction, result)
+		}
+		content = append(content, addition)
+	}
+	return content
+}
+
+//Test validates the additions against each detector in the chain, applying the rules of the ignoreConfig to their findings.
 //The results are passed in from detector to detector and thus collect all errors from all detectors
 func (dc *Chain) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
-	wd, _ := os.Getwd()
-	repo := git_repo.RepoLocatedAt(wd)
-	gitTrackedFilesAsAdditions := repo.TrackedFilesAsAdditions()
-	gitTrackedFilesAsAdditions = append(gitTrackedFilesAsAdditions, additions...)
+	result.fixtures = ignoreConfig.Fixtures
+	if _, err := ignoreConfig.FixtureFailingSeverity(); err != nil {
+		log.Errorf("Unable to downgrade the findings in the fixtures: %v", err)
+	}
+	suppressRules, err := ignoreConfig.SuppressRules()
+	if err != nil {
+		log.Errorf("Unable to apply the suppress expressions: %v", err)
+	}
+	result.suppressRules = suppressRules
+	severityActions, err := ignoreConfig.SeverityActions()
+	if err != nil {
+		log.Errorf("Unable to apply the severity actions: %v", err)
+	}
+	result.severityActions = severityActions
+	result.criticalPaths = ignoreConfig.CriticalPaths
+	allowList, err := ignoreConfig.AllowList()
+	if err != nil {
+		log.Errorf("Unable to apply the allowed patterns: %v", err)
+	}
+	result.allowList = &allowList
+	longLineAction, err := ignoreConfig.LongLineMode()
+	if err != nil {
+		log.Errorf("Unable to limit the length of the lines: %v", err)
+	}
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	recordScannedAdditions(additions, ignoreConfig, cc, &result.stats)
+	ignoreConfig.failBroadIgnores(additions, result)
+	scanned := map[git_repo.FilePath][]string{}
+	var contentAdditions []git_repo.Addition
 	for _, v := range dc.detectors {
-		v.Test(additions, ignoreConfig, result)
+		includedAdditions := additions
+		if v.category == "filecontent" {
+			if contentAdditions == nil {
+				contentAdditions = ignoreConfig.contentToScan(additions, longLineAction, cc, result)
+			}
+			includedAdditions = contentAdditions
+		}
+		if v.name != "" {
+			includedAdditions = ignoreConfig.includedAdditions(v.name, includedAdditions)
+		}
+		if len(includedAdditions) == 0 && len(additions) > 0 {
+			continue
+		}
+		if optIn, ok := v.detector.(optInDetector); ok && !optIn.isEnabled(ignoreConfig) {
+			continue
+		}
+		result.currentDetector = v.name
+		start := time.Now()
+		v.detector.Test(includedAdditions, ignoreConfig, result)
+		elapsed := time.Since(start)
+		result.currentDetector = ""
+		if v.name != "" {
+			result.auditDetectorRun(v.name, v.category, includedAdditions, ignoreConfig, cc, scanned)
+		}
+		if v.name != "" && scansAnyAddition(includedAdditions, ignoreConfig, cc, v.category) {
+			result.executedDetectors = append(result.executedDetectors, v.name)
+			result.stats.Detectors = append(result.stats.Detectors, DetectorStats{v.name, elapsed})
+		}
 	}
+	result.auditScanned(additions, scanned)
 }
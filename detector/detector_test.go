@@ -25,13 +25,56 @@ func TestValidationChainWithFailingValidationAlwaysFails(t *testing.T) {
 	assert.False(t, results.Successful(), "Expected validation chain with a failure to fail.")
 }
 
+func TestChainShouldRecordTheNamedDetectorsThatExecuted(t *testing.T) {
+	v := NewChain()
+	v.AddNamedDetector("passing", "filecontent", PassingDetection{})
+	v.AddNamedDetector("naming", "filename", PassingDetection{})
+	v.AddDetector(PassingDetection{})
+	results := NewDetectionResults()
+	ignores := NewTalismanRCIgnore([]byte("fileignoreconfig:\n- filename: '*'\n  ignore_detectors: [filecontent]\n"))
+	v.Test([]git_repo.Addition{git_repo.NewAddition("a.txt", []byte("contents"))}, ignores, results)
+
+	assert.Equal(t, []string{"passing", "naming"}, v.DetectorNames())
+	assert.Equal(t, []string{"naming"}, results.ExecutedDetectors(), "Expected a detector for which every addition is ignored to not have executed")
+}
+
+func TestChainShouldNotRunADisabledOptInDetector(t *testing.T) {
+	optIn := &OptInDetection{}
+	v := NewChain()
+	v.AddNamedDetector("opt_in", "filecontent", optIn)
+	results := NewDetectionResults()
+	v.Test([]git_repo.Addition{git_repo.NewAddition("a.txt", []byte("contents"))}, TalismanRCIgnore{}, results)
+
+	assert.Equal(t, 0, optIn.runs, "Expected a disabled opt-in detector to not be tested")
+	assert.NotContains(t, results.ExecutedDetectors(), "opt_in")
+	assert.Empty(t, results.Stats().Detectors, "Expected a disabled opt-in detector to not be timed")
+
+	optIn.enabled = true
+	v.Test([]git_repo.Addition{git_repo.NewAddition("a.txt", []byte("contents"))}, TalismanRCIgnore{}, results)
+
+	assert.Equal(t, 1, optIn.runs, "Expected an enabled opt-in detector to be tested")
+}
+
 type FailingDetection struct{}
 
 func (v FailingDetection) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
-	result.Fail("some_file", "filecontent","FAILED BY DESIGN", []string{})
+	result.Fail("some_file", "filecontent","FAILED BY DESIGN", []string{}, HighSeverity)
 }
 
 type PassingDetection struct{}
 
 func (p PassingDetection) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
 }
+
+type OptInDetection struct {
+	enabled bool
+	runs    int
+}
+
+func (o *OptInDetection) isEnabled(ignoreConfig TalismanRCIgnore) bool {
+	return o.enabled
+}
+
+func (o *OptInDetection) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	o.runs++
+}
@@ -0,0 +1,85 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const (
+	minEnvDumpLines    = 5
+	minEnvDumpDensity  = 0.8
+	minEnvSecretLength = 8
+)
+
+//envVariablePattern matches a KEY=VALUE line as printed by env, printenv and set, or as written to a Docker --env-file
+var envVariablePattern = regexp.MustCompile(`^(?:export\s+|declare\s+-x\s+)?([A-Za-z_][A-Za-z0-9_]*)=(.*)$`)
+
+//envSecretNamePattern matches the names of the variables that hold secrets. AUTH has to be a word of the name of its own, such as in BASIC_AUTH,
+//so that names such as GIT_AUTHOR_NAME or OAUTH_CALLBACK_URL are not taken for secrets.
+var envSecretNamePattern = regexp.MustCompile(`(?i)(token|secret|passw(or)?d|pwd|api[_-]?key|access[_-]?key|private[_-]?key|credential|(^|_)auth(_|$))`)
+
+//envPlaceholderPattern matches the values that stand in for a secret instead of being one, such as <your token>, ${TOKEN}, changeme or ****
+var envPlaceholderPattern = regexp.MustCompile(`(?i)^(<.*>|\$\{.*\}|\$[A-Za-z_][A-Za-z0-9_]*|\*+|x+|changeme|redacted|placeholder|none|null|true|false|[0-9]+)$`)
+
+//EnvDumpDetector flags the secrets in dumps of environment variables, such as the output of printenv or a Docker --env-file, committed by mistake.
+//A file is only considered to be a dump if most of its lines are KEY=VALUE pairs, and each secret key is reported once however often it appears.
+type EnvDumpDetector struct{}
+
+//NewEnvDumpDetector returns an EnvDumpDetector
+func NewEnvDumpDetector() *EnvDumpDetector {
+	return &EnvDumpDetector{}
+}
+
+//Test tests the contents of the Additions to ensure that they are not environment dumps holding secrets
+func (ed *EnvDumpDetector) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	for _, addition := range additions {
+		if ignoreConfig.Deny(addition, "filecontent") || cc.IsScanNotRequired(addition) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Ignoring addition as it was specified to be ignored.")
+			result.Ignore(addition.Path, "filecontent")
+			continue
+		}
+		for _, line := range ignoreConfig.reportableFindings(EnvDumpDetectorName, addition.Path, dumpedSecrets(string(addition.Data)), result) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Failing file as it is an environment dump holding a secret.")
+			result.FailAt(addition.Path, "filecontent", fmt.Sprintf("Expected file to not to contain environment dumps with secrets such as: %s", line), addition.Commits, HighSeverity, findingIn(addition.Data, line))
+		}
+	}
+}
+
+//dumpedSecrets returns the first KEY=VALUE line of every secret key of the dump, or nothing if the content is not a dump
+func dumpedSecrets(content string) []string {
+	var lines []string
+	seenKeys := map[string]bool{}
+	variables, nonBlank := 0, 0
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		nonBlank++
+		match := envVariablePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		variables++
+		key, value := match[1], strings.Trim(match[2], `"'`)
+		if seenKeys[key] || !envSecretNamePattern.MatchString(key) || len(value) < minEnvSecretLength || envPlaceholderPattern.MatchString(value) {
+			continue
+		}
+		seenKeys[key] = true
+		lines = append(lines, line)
+	}
+	if variables < minEnvDumpLines || float64(variables) < minEnvDumpDensity*float64(nonBlank) {
+		return nil
+	}
+	return lines
+}
@@ -0,0 +1,79 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const envDump = `HOME=/home/deploy
+PATH=/usr/local/bin:/usr/bin:/bin
+SHELL=/bin/bash
+DATABASE_PASSWORD=s3cr3tPassw0rd!
+LANG=en_US.UTF-8
+GITHUB_TOKEN=ghp_a1b2c3d4e5f6g7h8i9j0
+AWS_SECRET_ACCESS_KEY=wJalrXUtnFEMIK7MDENGbPxRfiCYzEXAMPLEKEY
+DATABASE_PASSWORD=s3cr3tPassw0rd!
+API_KEY_PLACEHOLDER=<your api key>
+SESSION_TOKEN=changeme
+AUTH_ENABLED=true
+`
+
+func TestShouldReportEachSecretOfAnEnvDumpOnce(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("printenv.log", []byte(envDump + envDump))}
+
+	NewEnvDumpDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	var messages []string
+	for _, failure := range results.GetFailures("printenv.log") {
+		messages = append(messages, failure.Message)
+	}
+	assert.Equal(t, []string{
+		"Expected file to not to contain environment dumps with secrets such as: DATABASE_PASSWORD=s3cr3tPassw0rd!",
+		"Expected file to not to contain environment dumps with secrets such as: GITHUB_TOKEN=ghp_a1b2c3d4e5f6g7h8i9j0",
+		"Expected file to not to contain environment dumps with secrets such as: AWS_SECRET_ACCESS_KEY=wJalrXUtnFEMIK7MDENGbPxRfiCYzEXAMPLEKEY",
+	}, messages)
+}
+
+func TestShouldNotFlagBenignConfigLists(t *testing.T) {
+	results := NewDetectionResults()
+	config := "LOG_LEVEL=debug\nPORT=8080\nHOST=0.0.0.0\nWORKERS=4\nREGION=eu-west-1\nFEATURE_FLAGS=search,checkout\n"
+	additions := []git_repo.Addition{git_repo.NewAddition("env.txt", []byte(config))}
+
+	NewEnvDumpDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasFailures(), "Expected a config list without secrets to not be flagged")
+}
+
+func TestShouldNotTakeVariablesMerelyContainingAuthForSecrets(t *testing.T) {
+	results := NewDetectionResults()
+	config := "GIT_AUTHOR_NAME=Jane Developer\nAUTHOR_EMAIL=jane.developer@example.com\nOAUTH_CALLBACK_URL=https://example.com/oauth/callback\nPORT=8080\nHOST=0.0.0.0\nLOG_LEVEL=debug\n"
+	additions := []git_repo.Addition{git_repo.NewAddition("env.txt", []byte(config))}
+
+	NewEnvDumpDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasFailures(), "Expected the author and callback variables to not be flagged")
+}
+
+func TestShouldFlagAuthVariables(t *testing.T) {
+	results := NewDetectionResults()
+	config := "PORT=8080\nBASIC_AUTH=admin:s3cr3tpassw0rd\nHOST=0.0.0.0\nLOG_LEVEL=debug\nWORKERS=4\n"
+	additions := []git_repo.Addition{git_repo.NewAddition("env.txt", []byte(config))}
+
+	NewEnvDumpDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.Len(t, results.GetFailures("env.txt"), 1)
+}
+
+func TestShouldNotTreatSparseAssignmentsAsAnEnvDump(t *testing.T) {
+	results := NewDetectionResults()
+	script := "#!/bin/sh\nset -e\nDB_PASSWORD=hunter2hunter2\ncd /app\nmake build\nmake test\n./deploy.sh\nexit 0\n"
+	additions := []git_repo.Addition{git_repo.NewAddition("deploy.sh", []byte(script))}
+
+	NewEnvDumpDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasFailures(), "Expected a script with a few assignments to not be treated as a dump")
+}
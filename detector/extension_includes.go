@@ -0,0 +1,52 @@
+package detector
+
+import (
+	"path"
+	"strings"
+
+	"talisman/git_repo"
+)
+
+//includesExtension answers true if the addition has one of the extensions, which are compared ignoring case and with or without their leading dot
+func includesExtension(addition git_repo.Addition, extensions []string) bool {
+	extension := strings.ToLower(path.Ext(string(addition.Name)))
+	for _, included := range extensions {
+		included = strings.ToLower(strings.TrimSpace(included))
+		if !strings.HasPrefix(included, ".") {
+			included = "." + included
+		}
+		if extension == included {
+			return true
+		}
+	}
+	return false
+}
+
+//includedAdditions returns the additions that the detector runs on. A detector with extension includes in the detector_extension_includes
+//of the .talismanrc only runs on the files with one of those extensions, and is skipped when there are none, while a detector without includes runs on all of the additions.
+func (i TalismanRCIgnore) includedAdditions(detectorName string, additions []git_repo.Addition) []git_repo.Addition {
+	extensions, ok := i.DetectorExtensionIncludes[detectorName]
+	if !ok || len(extensions) == 0 {
+		return additions
+	}
+	var included []git_repo.Addition
+	for _, addition := range additions {
+		if includesExtension(addition, extensions) {
+			included = append(included, addition)
+		}
+	}
+	return included
+}
+
+func mergeExtensionIncludes(includes ...map[string][]string) map[string][]string {
+	var result map[string][]string
+	for _, include := range includes {
+		for detectorName, extensions := range include {
+			if result == nil {
+				result = map[string][]string{}
+			}
+			result[detectorName] = append(result[detectorName], extensions...)
+		}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//recordingDetection records the paths of the additions it was run on
+type recordingDetection struct {
+	paths *[]string
+}
+
+func (r recordingDetection) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	for _, addition := range additions {
+		*r.paths = append(*r.paths, string(addition.Path))
+	}
+}
+
+var mixedAdditions = []git_repo.Addition{
+	git_repo.NewAddition("config/app.properties", []byte("password=secret")),
+	git_repo.NewAddition("config/app.yml", []byte("password: secret")),
+	git_repo.NewAddition("Main.java", []byte("class Main {}")),
+	git_repo.NewAddition("config/LEGACY.PROPERTIES", []byte("password=secret")),
+}
+
+func TestDetectorShouldOnlyRunOnItsIncludedExtensions(t *testing.T) {
+	var propertiesPaths, otherPaths []string
+	chain := NewChain()
+	chain.AddNamedDetector("properties", "filecontent", recordingDetection{&propertiesPaths})
+	chain.AddNamedDetector("other", "filecontent", recordingDetection{&otherPaths})
+	ignores := NewTalismanRCIgnore([]byte("detector_extension_includes:\n  properties: [.properties]\n"))
+
+	chain.Test(mixedAdditions, ignores, NewDetectionResults())
+
+	assert.Equal(t, []string{"config/app.properties", "config/LEGACY.PROPERTIES"}, propertiesPaths, "Expected the detector to only run on the included extensions, ignoring case")
+	assert.Len(t, otherPaths, 4, "Expected a detector without includes to run on all of the files")
+}
+
+func TestDetectorShouldBeSkippedWhenNoFileHasAnIncludedExtension(t *testing.T) {
+	var paths []string
+	chain := NewChain()
+	chain.AddNamedDetector("properties", "filecontent", recordingDetection{&paths})
+	chain.AddNamedDetector("other", "filecontent", PassingDetection{})
+	ignores := NewTalismanRCIgnore([]byte("detector_extension_includes:\n  properties: [properties, ini]\n"))
+	results := NewDetectionResults()
+
+	chain.Test(mixedAdditions[1:3], ignores, results)
+
+	assert.Empty(t, paths)
+	assert.Equal(t, []string{"other"}, results.ExecutedDetectors(), "Expected a detector that was skipped everywhere to not have executed")
+}
+
+func TestExtensionIncludesShouldBeMergedAcrossTheConfigChain(t *testing.T) {
+	base := NewTalismanRCIgnore([]byte("detector_extension_includes:\n  oauth: [.json]\n"))
+	override := NewTalismanRCIgnore([]byte("detector_extension_includes:\n  oauth: [.jsonc]\n  cipipeline: [.yml]\n"))
+
+	merged := base.MergeWith(override)
+
+	assert.Equal(t, map[string][]string{"oauth": {".json", ".jsonc"}, "cipipeline": {".yml"}}, merged.DetectorExtensionIncludes)
+}
+
+func TestChainShouldHonorTheExtensionIncludesOfTheContentDetectors(t *testing.T) {
+	additions := []git_repo.Addition{git_repo.NewAddition("token.js", []byte(googleTokenJSON))}
+	ignores := NewTalismanRCIgnore([]byte("detector_extension_includes:\n  oauth: [.json]\n"))
+	results := NewDetectionResults()
+
+	NewChain().AddNamedDetector(OAuthTokenDetectorName, "filecontent", NewOAuthTokenDetector()).Test(additions, ignores, results)
+
+	assert.False(t, results.HasFailures(), "Expected the oauth detector to not run on a file without the .json extension")
+}
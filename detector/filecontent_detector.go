@@ -1,7 +1,6 @@
 package detector
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
 
@@ -16,6 +15,7 @@ type FileContentDetector struct {
 	base64Detector     *Base64Detector
 	hexDetector        *HexDetector
 	creditCardDetector *CreditCardDetector
+	urlSafeDetector    *URLSafeDetector
 }
 
 func NewFileContentDetector() *FileContentDetector {
@@ -23,6 +23,7 @@ func NewFileContentDetector() *FileContentDetector {
 	fc.base64Detector = NewBase64Detector()
 	fc.hexDetector = NewHexDetector()
 	fc.creditCardDetector = NewCreditCardDetector()
+	fc.urlSafeDetector = NewURLSafeDetector()
 	return &fc
 }
 
@@ -49,48 +50,73 @@ func (fc *FileContentDetector) Test(additions []git_repo.Addition, ignoreConfig
 			addition.Data = data
 		}
 
-		base64Results := fc.detectFile(addition.Data, checkBase64)
-		fillBase46DetectionResults(base64Results, addition, result)
+		context := ignoreConfig.ContextDetector
+		base64Findings := fc.detectFile(addition.Data, checkBase64)
+		base64Results := ignoreConfig.reportableFindings(Base64DetectorName, addition.Path, context.findingsInContext(addition.Data, base64Findings), result)
+		fillBase46DetectionResults(base64Results, addition, result, ignoreConfig.MergesAdjacentFindings())
 
-		hexResults := fc.detectFile(addition.Data, checkHex)
-		fillHexDetectionResults(hexResults, addition, result)
+		hexResults := ignoreConfig.reportableFindings(HexDetectorName, addition.Path, context.findingsInContext(addition.Data, fc.detectFile(addition.Data, checkHex)), result)
+		fillHexDetectionResults(hexResults, addition, result, ignoreConfig.MergesAdjacentFindings())
 
-		creditCardResults := fc.detectFile(addition.Data, checkCreditCardNumber)
+		urlSafeResults := ignoreConfig.reportableFindings(URLSafeDetectorName, addition.Path, context.findingsInContext(addition.Data, withoutFindings(fc.detectFile(addition.Data, checkURLSafe), base64Findings)), result)
+		fillURLSafeDetectionResults(urlSafeResults, addition, result, ignoreConfig.MergesAdjacentFindings())
+
+		creditCardResults := ignoreConfig.reportableFindings(CreditCardDetectorName, addition.Path, fc.detectFile(addition.Data, checkCreditCardNumber), result)
 		fillCreditCardDetectionResults(creditCardResults, addition, result)
 	}
 }
 
-func fillResults(results []string, addition git_repo.Addition, result *DetectionResults, info string, output string) {
-	for _, res := range results {
-		if res != "" {
-			log.WithFields(log.Fields{
-				"filePath": addition.Path,
-			}).Info(info)
-			if string(addition.Name) == DefaultRCFileName {
-				result.Warn(addition.Path, "filecontent", fmt.Sprintf(output, res), []string{})
-			} else {
-				result.Fail(addition.Path, "filecontent", fmt.Sprintf(output, res), []string{})
-			}
+//fillResults reports the findings as found by the named detector, so that the suppress expressions and the audit log can tell
+//the base64, hex, URL-safe and credit card findings of the content detector apart
+func fillResults(detectorName string, results []string, addition git_repo.Addition, result *DetectionResults, info string, output string, severity Severity, mergeAdjacent bool) {
+	chainDetector := result.currentDetector
+	result.currentDetector = detectorName
+	defer func() { result.currentDetector = chainDetector }()
+	for _, reported := range reportedFindings(addition.Data, results, mergeAdjacent) {
+		log.WithFields(log.Fields{
+			"filePath": addition.Path,
+		}).Info(info)
+		if string(addition.Name) == DefaultRCFileName {
+			result.WarnAt(addition.Path, "filecontent", reported.message(output), []string{}, severity, reported.finding)
+		} else {
+			result.FailAt(addition.Path, "filecontent", reported.message(output), []string{}, severity, reported.finding)
 		}
 	}
 }
 
-func fillBase46DetectionResults(base64Results []string, addition git_repo.Addition, result *DetectionResults) {
+func fillBase46DetectionResults(base64Results []string, addition git_repo.Addition, result *DetectionResults, mergeAdjacent bool) {
 	const info = "Failing file as it contains a base64 encoded text."
 	const output = "Expected file to not to contain base64 encoded texts such as: %s"
-	fillResults(base64Results, addition, result, info, output)
+	fillResults(Base64DetectorName, base64Results, addition, result, info, output, MediumSeverity, mergeAdjacent)
 }
 
 func fillCreditCardDetectionResults(creditCardResults []string, addition git_repo.Addition, result *DetectionResults) {
 	const info = "Failing file as it contains a potential credit card number."
 	const output = "Expected file to not to contain credit card numbers such as: %s"
-	fillResults(creditCardResults, addition, result, info, output)
+	fillResults(CreditCardDetectorName, creditCardResults, addition, result, info, output, HighSeverity, false)
+}
+
+func fillURLSafeDetectionResults(urlSafeResults []string, addition git_repo.Addition, result *DetectionResults, mergeAdjacent bool) {
+	const info = "Failing file as it contains a URL-safe encoded text."
+	const output = "Expected file to not to contain URL-safe encoded texts such as: %s"
+	fillResults(URLSafeDetectorName, urlSafeResults, addition, result, info, output, MediumSeverity, mergeAdjacent)
 }
 
-func fillHexDetectionResults(hexResults []string, addition git_repo.Addition, result *DetectionResults) {
+//withoutFindings returns the findings that are not among the already reported ones, so that a text is not reported twice
+func withoutFindings(findings []string, reported []string) []string {
+	var result []string
+	for _, finding := range findings {
+		if !contains(reported, finding) {
+			result = append(result, finding)
+		}
+	}
+	return result
+}
+
+func fillHexDetectionResults(hexResults []string, addition git_repo.Addition, result *DetectionResults, mergeAdjacent bool) {
 	const info = "Failing file as it contains a hex encoded text."
 	const output = "Expected file to not to contain hex encoded texts such as: %s"
-	fillResults(hexResults, addition, result, info, output)
+	fillResults(HexDetectorName, hexResults, addition, result, info, output, MediumSeverity, mergeAdjacent)
 }
 
 func (fc *FileContentDetector) detectFile(data []byte, getResult fn) []string {
@@ -130,6 +156,10 @@ func checkCreditCardNumber(fc *FileContentDetector, word string) string {
 	return fc.creditCardDetector.checkCreditCardNumber(word)
 }
 
+func checkURLSafe(fc *FileContentDetector, word string) string {
+	return fc.urlSafeDetector.checkURLSafeEncoding(word)
+}
+
 func checkHex(fc *FileContentDetector, word string) string {
 	return fc.hexDetector.checkHexEncoding(word)
 }
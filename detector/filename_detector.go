@@ -92,7 +92,7 @@ func (fd FileNameDetector) Test(additions []git_repo.Addition, ignoreConfig Tali
 					"filePath": addition.Path,
 					"pattern":  pattern,
 				}).Info("Failing file as it matched pattern.")
-				result.Fail(addition.Path, "filename", fmt.Sprintf("The file name %q failed checks against the pattern %s", addition.Path, pattern), addition.Commits)
+				result.Fail(addition.Path, "filename", fmt.Sprintf("The file name %q failed checks against the pattern %s", addition.Path, pattern), addition.Commits, MediumSeverity)
 			}
 		}
 	}
@@ -0,0 +1,43 @@
+package detector
+
+import (
+	"gopkg.in/yaml.v2"
+)
+
+//RefreshChecksums updates the checksum of the file ignores of the .talismanrc contents to the given checksums of their file names.
+//The rest of the contents, including comments, are kept as they are. Only the file ignores that have a checksum are updated.
+//The updated ignores are returned along with the new contents, as are the ignores without a checksum to update to, whose files are gone.
+func RefreshChecksums(contents []byte, checksums map[string]string) ([]byte, []FileIgnoreConfig, []FileIgnoreConfig, error) {
+	editor := newRCFileEditor(contents)
+	block := editor.listBlock(fileIgnoreConfigKey)
+	if block == nil {
+		return contents, nil, nil, nil
+	}
+	var refreshed, stale []FileIgnoreConfig
+	for _, entry := range block.entries {
+		fileIgnoreConfig, err := editor.fileIgnoreConfigOf(block, entry)
+		if err != nil {
+			return contents, nil, nil, err
+		}
+		if isEmptyString(fileIgnoreConfig.Checksum) {
+			continue
+		}
+		checksum, exists := checksums[fileIgnoreConfig.FileName]
+		if !exists {
+			stale = append(stale, fileIgnoreConfig)
+			continue
+		}
+		if checksum != fileIgnoreConfig.Checksum && editor.replaceEntryValue(entry, "checksum", checksum) {
+			fileIgnoreConfig.Checksum = checksum
+			refreshed = append(refreshed, fileIgnoreConfig)
+		}
+	}
+	if len(refreshed) == 0 {
+		return contents, nil, stale, nil
+	}
+	result := editor.contents()
+	if err := yaml.Unmarshal(result, &TalismanRCIgnore{}); err != nil {
+		return contents, nil, nil, err
+	}
+	return result, refreshed, stale, nil
+}
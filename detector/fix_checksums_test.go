@@ -0,0 +1,72 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const rcFileWithChecksums = `# ignores reviewed by the security team
+fileignoreconfig:
+# fixture used by the integration tests
+- filename: fixtures/server.pem
+  checksum: abc # refreshed whenever the fixture is regenerated
+  comment: test certificate, not used in production
+- filename: fixtures/removed.pem
+  checksum: def
+- filename: docs/*.md
+  ignore_detectors: [filecontent]
+- filename: fixtures/unchanged.key
+  checksum: "123"
+scopeconfig:
+- scope: go
+`
+
+func TestShouldUpdateTheChecksumsOfChangedFiles(t *testing.T) {
+	checksums := map[string]string{"fixtures/server.pem": "0f1e2d", "docs/*.md": "987", "fixtures/unchanged.key": "123"}
+
+	fixed, refreshed, stale, err := RefreshChecksums([]byte(rcFileWithChecksums), checksums)
+
+	assert.NoError(t, err)
+	assert.Len(t, refreshed, 1)
+	assert.Equal(t, "fixtures/server.pem", refreshed[0].FileName)
+	assert.Equal(t, "0f1e2d", refreshed[0].Checksum)
+	assert.Equal(t, `# ignores reviewed by the security team
+fileignoreconfig:
+# fixture used by the integration tests
+- filename: fixtures/server.pem
+  checksum: 0f1e2d # refreshed whenever the fixture is regenerated
+  comment: test certificate, not used in production
+- filename: fixtures/removed.pem
+  checksum: def
+- filename: docs/*.md
+  ignore_detectors: [filecontent]
+- filename: fixtures/unchanged.key
+  checksum: "123"
+scopeconfig:
+- scope: go
+`, string(fixed), "Expected only the stale checksum to change, and the comments to be kept")
+	assert.Len(t, stale, 1)
+	assert.Equal(t, "fixtures/removed.pem", stale[0].FileName, "Expected the ignore whose file is gone to be reported")
+}
+
+func TestShouldNotAddChecksumsToIgnoresWithoutOne(t *testing.T) {
+	contents := "fileignoreconfig:\n- filename: docs/*.md\n  ignore_detectors: [filecontent]\n"
+
+	fixed, refreshed, stale, err := RefreshChecksums([]byte(contents), map[string]string{"docs/*.md": "987"})
+
+	assert.NoError(t, err)
+	assert.Empty(t, refreshed)
+	assert.Empty(t, stale)
+	assert.Equal(t, contents, string(fixed))
+}
+
+func TestShouldLeaveTheFileUntouchedWhenTheChecksumsAreUpToDate(t *testing.T) {
+	contents := "fileignoreconfig:\n- filename: fixtures/server.pem\n  checksum: abc\n"
+
+	fixed, refreshed, _, err := RefreshChecksums([]byte(contents), map[string]string{"fixtures/server.pem": "abc"})
+
+	assert.NoError(t, err)
+	assert.Empty(t, refreshed)
+	assert.Equal(t, contents, string(fixed))
+}
@@ -0,0 +1,107 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"talisman/git_repo"
+)
+
+const (
+	//DefaultFixtureFailingEntropy is the entropy above which a finding in a test fixture still fails the run
+	DefaultFixtureFailingEntropy = 5.0
+	//DefaultFixtureFailingSeverity is the severity from which a finding in a test fixture still fails the run
+	DefaultFixtureFailingSeverity = CriticalSeverity
+)
+
+var fixtureTokenSeparators = regexp.MustCompile(`[\s:=,;"'<>()\[\]{}]+`)
+
+//FixtureConfig represents the files holding test fixtures, which legitimately contain fake secrets.
+//Findings in these files are reported as warnings, unless their entropy or severity is high enough for them to look like real secrets.
+type FixtureConfig struct {
+	Paths           []string `yaml:"paths,omitempty"`
+	FailingEntropy  float64  `yaml:"failing_entropy,omitempty"`
+	FailingSeverity string   `yaml:"failing_severity,omitempty"`
+}
+
+func (c FixtureConfig) mergeWith(other FixtureConfig) FixtureConfig {
+	result := FixtureConfig{FailingEntropy: c.FailingEntropy, FailingSeverity: c.FailingSeverity}
+	result.Paths = append(append(result.Paths, c.Paths...), other.Paths...)
+	if other.FailingEntropy != 0 {
+		result.FailingEntropy = other.FailingEntropy
+	}
+	if other.FailingSeverity != "" {
+		result.FailingSeverity = other.FailingSeverity
+	}
+	return result
+}
+
+func (c FixtureConfig) isFixture(filePath git_repo.FilePath) bool {
+	addition := git_repo.NewAddition(string(filePath), []byte{})
+	for _, pattern := range c.Paths {
+		if pattern != "" && addition.Matches(pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c FixtureConfig) failingEntropy() float64 {
+	if c.FailingEntropy > 0 {
+		return c.FailingEntropy
+	}
+	return DefaultFixtureFailingEntropy
+}
+
+//FixtureFailingSeverity parses the failing_severity of the fixtures, which is critical unless configured otherwise, failing on an unknown severity
+func (ignore TalismanRCIgnore) FixtureFailingSeverity() (Severity, error) {
+	return ignore.Fixtures.failingSeverity()
+}
+
+func (c FixtureConfig) failingSeverity() (Severity, error) {
+	if isEmptyString(c.FailingSeverity) {
+		return DefaultFixtureFailingSeverity, nil
+	}
+	severity, err := SeverityFromString(c.FailingSeverity)
+	if err != nil {
+		return 0, fmt.Errorf("invalid failing_severity of the fixtures: %v", err)
+	}
+	return severity, nil
+}
+
+//isDowngraded answers true if a finding in the file should be reported as a warning instead of a failure.
+//The entropy is only measured on the text matched by the finding, so a finding on the whole file, such as that of its name or size, is judged by its severity alone.
+//With a malformed failing_severity, no finding is downgraded.
+func (c FixtureConfig) isDowngraded(filePath git_repo.FilePath, severity Severity, finding Finding) bool {
+	failingSeverity, err := c.failingSeverity()
+	if err != nil || !c.isFixture(filePath) || severity >= failingSeverity {
+		return false
+	}
+	if finding.Text == "" {
+		return true
+	}
+	return findingEntropy(finding.Text) < c.failingEntropy()
+}
+
+//findingEntropy returns the highest entropy among the tokens of the matched text, so that a key name like password does not dilute the entropy of its value
+func findingEntropy(text string) float64 {
+	entropy := &Entropy{}
+	highest := 0.0
+	for _, token := range fixtureTokenSeparators.Split(text, -1) {
+		if value := entropy.GetShannonEntropy(token, uniqueCharacters(token)); value > highest {
+			highest = value
+		}
+	}
+	return highest
+}
+
+func uniqueCharacters(text string) string {
+	var unique strings.Builder
+	for _, c := range text {
+		if !strings.ContainsRune(unique.String(), c) {
+			unique.WriteRune(c)
+		}
+	}
+	return unique.String()
+}
@@ -0,0 +1,85 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fixtureIgnores = NewTalismanRCIgnore([]byte(`fixtures:
+  paths:
+  - test/fixtures/
+`))
+
+func TestShouldWarnAboutLowEntropySecretsInFixtures(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("test/fixtures/users.yml", []byte("password: testpassword"))}
+
+	NewChain().AddDetector(NewPatternDetector()).Test(additions, fixtureIgnores, results)
+
+	assert.False(t, results.HasFailures(), "Expected the fake secret of the fixture to not fail the run")
+	assert.True(t, results.HasWarnings(), "Expected the fake secret of the fixture to be reported as a warning")
+}
+
+func TestShouldFailOnRealLookingSecretsInFixtures(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("test/fixtures/users.yml", []byte("password: aZ3xQ9wE7rT1yU5iO2pL8kJ4hG6fD0sMnBvCqWe"))}
+
+	NewChain().AddDetector(NewPatternDetector()).Test(additions, fixtureIgnores, results)
+
+	assert.True(t, results.HasFailures(), "Expected a real looking secret to fail the run even in a fixture")
+}
+
+func TestShouldFailOnLowEntropySecretsOutsideOfFixtures(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("config/users.yml", []byte("password: testpassword"))}
+
+	NewChain().AddDetector(NewPatternDetector()).Test(additions, fixtureIgnores, results)
+
+	assert.True(t, results.HasFailures(), "Expected files outside of the fixtures to fail as usual")
+}
+
+func TestShouldFailOnSevereFindingsInFixtures(t *testing.T) {
+	results := NewDetectionResults()
+	ignores := NewTalismanRCIgnore([]byte("fixtures:\n  paths: [test/fixtures/]\n  failing_severity: high\n"))
+	additions := []git_repo.Addition{git_repo.NewAddition("test/fixtures/users.yml", []byte("password: testpassword"))}
+
+	NewChain().AddDetector(NewPatternDetector()).Test(additions, ignores, results)
+
+	assert.True(t, results.HasFailures(), "Expected findings at the failing severity to fail even in a fixture")
+}
+
+func TestShouldJudgeWholeFileFindingsInFixturesByTheirSeverity(t *testing.T) {
+	ignores := NewTalismanRCIgnore([]byte("fixtures:\n  paths: [test/fixtures/]\n  failing_severity: high\n"))
+	for severity, failing := range map[Severity]bool{MediumSeverity: false, HighSeverity: true} {
+		results := NewDetectionResults()
+		results.fixtures = ignores.Fixtures
+
+		results.Fail("test/fixtures/private.pem", "filename", "The file name \"private.pem\" failed checks", []string{}, severity)
+
+		assert.Equal(t, failing, results.HasFailures(), "Expected a whole file finding of %s severity to fail: %v", severity, failing)
+		assert.Equal(t, !failing, results.HasWarnings(), "Expected a whole file finding of %s severity to warn: %v", severity, !failing)
+	}
+}
+
+func TestUnknownFixtureFailingSeveritiesShouldBeReportedAsConfigErrors(t *testing.T) {
+	ignores := NewTalismanRCIgnore([]byte("fixtures:\n  paths: [test/fixtures/]\n  failing_severity: severe\n"))
+
+	_, err := ignores.FixtureFailingSeverity()
+
+	if assert.Error(t, err) {
+		assert.Equal(t, `invalid failing_severity of the fixtures: unknown severity "severe", expected one of low, medium, high or critical`, err.Error())
+	}
+}
+
+func TestUnknownFixtureFailingSeveritiesShouldNotDowngradeAnything(t *testing.T) {
+	results := NewDetectionResults()
+	ignores := NewTalismanRCIgnore([]byte("fixtures:\n  paths: [test/fixtures/]\n  failing_severity: severe\n"))
+	additions := []git_repo.Addition{git_repo.NewAddition("test/fixtures/users.yml", []byte("password: testpassword"))}
+
+	NewChain().AddDetector(NewPatternDetector()).Test(additions, ignores, results)
+
+	assert.True(t, results.HasFailures(), "Expected a malformed failing severity to not downgrade the finding")
+}
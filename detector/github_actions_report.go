@@ -0,0 +1,74 @@
+package detector
+
+import (
+	"fmt"
+	"strings"
+
+	"talisman/git_repo"
+)
+
+//githubActionsMessageEscaper escapes the characters that GitHub Actions does not allow in the message of a workflow command
+var githubActionsMessageEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+
+//githubActionsPropertyEscaper escapes the characters that GitHub Actions does not allow in the properties of a workflow command
+var githubActionsPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+//GithubActionsReport returns the failures and warnings of the current run as GitHub Actions workflow commands, which are rendered inline on the diff of a pull request.
+//Failures of medium severity and above are reported with ::error, while the other failures and all the warnings are reported with ::warning.
+//The secrets are masked in the messages, as the workflow logs are visible to everyone with access to the repository.
+func (r *DetectionResults) GithubActionsReport() string {
+	var result strings.Builder
+	for _, resultDetails := range r.sortedResults() {
+		for _, detail := range resultDetails.FailureList {
+			command := "warning"
+			if detail.Severity >= MediumSeverity {
+				command = "error"
+			}
+			result.WriteString(githubActionsCommand(command, resultDetails.Filename, detail))
+		}
+		for _, detail := range resultDetails.WarningList {
+			result.WriteString(githubActionsCommand("warning", resultDetails.Filename, detail))
+		}
+	}
+	return result.String()
+}
+
+func githubActionsCommand(command string, filePath git_repo.FilePath, detail Details) string {
+	properties := []string{"file=" + githubActionsPropertyEscaper.Replace(string(filePath))}
+	if detail.Line > 0 {
+		properties = append(properties, fmt.Sprintf("line=%d", detail.Line))
+	}
+	if detail.EndLine > detail.Line {
+		properties = append(properties, fmt.Sprintf("endLine=%d", detail.EndLine))
+	}
+	if detail.Column > 0 {
+		properties = append(properties, fmt.Sprintf("col=%d", detail.Column))
+	}
+	return fmt.Sprintf("::%s %s::%s\n", command, strings.Join(properties, ","), githubActionsMessageEscaper.Replace(maskedMessage(detail)))
+}
+
+//maskedMessage returns the message of the detail with the value of its secret masked, line by line for the secrets spanning several lines
+func maskedMessage(detail Details) string {
+	if detail.Secret == "" {
+		return detail.Message
+	}
+	message := detail.Message
+	for _, secret := range strings.Split(detail.Secret, "\n") {
+		if secret == "" {
+			continue
+		}
+		value := assignedValue(secret)
+		masked := strings.Replace(secret, value, maskSecret(value), 1)
+		message = strings.Replace(message, secret, masked, -1)
+	}
+	return message
+}
+
+//maskSecret keeps the first characters of long secrets so that they can still be told apart, and masks the rest
+func maskSecret(secret string) string {
+	runes := []rune(secret)
+	if len(runes) <= 8 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:2]) + strings.Repeat("*", len(runes)-2)
+}
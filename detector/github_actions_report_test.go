@@ -0,0 +1,48 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldReportFindingsAsGithubActionsWorkflowCommands(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("config/app.yml", []byte("name: app\n  password: UnsafePassword\n"))}
+
+	NewPatternDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.Equal(t, "::error file=config/app.yml,line=2,col=3::Potential secret pattern : password: Un************\n", results.GithubActionsReport())
+}
+
+func TestShouldReportWarningsAndLowSeverityFailuresAsGithubActionsWarnings(t *testing.T) {
+	results := NewDetectionResults()
+	results.Fail("big.bin", "filesize", "The file name \"big.bin\" is too large", []string{}, LowSeverity)
+	results.WarnAt("a.txt", "filecontent", "Line 3 was not scanned", []string{}, LowSeverity, Finding{Position: Position{Line: 3, Column: 1}})
+
+	assert.Equal(t, `::warning file=a.txt,line=3,col=1::Line 3 was not scanned
+::warning file=big.bin::The file name "big.bin" is too large
+`, results.GithubActionsReport())
+}
+
+func TestShouldEscapeGithubActionsWorkflowCommands(t *testing.T) {
+	results := NewDetectionResults()
+	results.Fail("dir,with:chars/key.pem", "filename", "100% sure\nit is a key", []string{}, HighSeverity)
+
+	assert.Equal(t, "::error file=dir%2Cwith%3Achars/key.pem::100%25 sure%0Ait is a key\n", results.GithubActionsReport())
+}
+
+func TestShouldMaskSecrets(t *testing.T) {
+	assert.Equal(t, "****", maskSecret("abcd"))
+	assert.Equal(t, "wJ**********", maskSecret("wJalrXUtnFEM"))
+}
+
+func TestShouldReportTheLineRangeOfFindingsSpanningSeveralLines(t *testing.T) {
+	results := NewDetectionResults()
+	finding := Finding{Text: "MIIBVQIBADAN\nBgkqhkiG9w0B", Position: Position{Line: 2, Column: 1}, EndLine: 3}
+	results.FailAt("server.key", "filecontent", "Expected file to not to contain base64 encoded texts such as: MIIBVQIBADAN (lines 2 to 3)", []string{}, MediumSeverity, finding)
+
+	assert.Equal(t, "::error file=server.key,line=2,endLine=3,col=1::Expected file to not to contain base64 encoded texts such as: MI********** (lines 2 to 3)\n", results.GithubActionsReport())
+}
@@ -0,0 +1,251 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	minHTTPFixtureSecretLength  = 16
+	httpFixtureEntropyThreshold = 3.5
+	httpFixtureEntropyChars     = BASE64_CHARS + "-_.~%"
+)
+
+//httpFixtureRecordKeys are the top level keys of the recorded HTTP interactions, as written by the VCR libraries of Ruby, Python and Go
+var httpFixtureRecordKeys = []string{"http_interactions", "interactions"}
+
+//benignHTTPParams are the query parameters, headers and cookies that hold identifiers, hashes or dates rather than secrets, even when their values are random looking
+var benignHTTPParams = map[string]bool{
+	"page": true, "per_page": true, "limit": true, "offset": true, "sort": true, "order": true, "q": true, "query": true, "search": true,
+	"lang": true, "locale": true, "format": true, "callback": true, "v": true, "version": true, "fields": true, "include": true,
+	"accept": true, "accept-encoding": true, "accept-language": true, "age": true, "cache-control": true, "cf-ray": true, "connection": true,
+	"content-encoding": true, "content-length": true, "content-type": true, "content-security-policy": true, "date": true, "etag": true,
+	"expires": true, "host": true, "if-modified-since": true, "if-none-match": true, "last-modified": true, "link": true, "location": true,
+	"origin": true, "referer": true, "server": true, "strict-transport-security": true, "traceparent": true, "tracestate": true,
+	"transfer-encoding": true, "user-agent": true, "vary": true, "via": true, "x-amz-cf-id": true, "x-amz-id-2": true, "x-amz-request-id": true,
+	"x-correlation-id": true, "x-github-request-id": true, "x-powered-by": true, "x-request-id": true, "x-runtime": true,
+	"_ga": true, "_gid": true, "_gat": true,
+}
+
+//httpParam is a query parameter, header or cookie of a recorded request or response
+type httpParam struct {
+	kind  string
+	name  string
+	value string
+}
+
+//HTTPFixtureDetector flags the session tokens and API keys that recorded HTTP fixtures, such as VCR cassettes and HAR files,
+//embed in the query parameters, headers and cookies of the requests and responses
+type HTTPFixtureDetector struct {
+	entropy *Entropy
+}
+
+//NewHTTPFixtureDetector returns an HTTPFixtureDetector
+func NewHTTPFixtureDetector() *HTTPFixtureDetector {
+	return &HTTPFixtureDetector{&Entropy{}}
+}
+
+//Test tests the HTTP fixtures among the Additions to ensure that the interactions they record don't hold secrets
+func (hd *HTTPFixtureDetector) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	for _, addition := range additions {
+		document, isFixture := httpFixtureDocument(addition)
+		if !isFixture {
+			continue
+		}
+		if ignoreConfig.Deny(addition, "filecontent") || cc.IsScanNotRequired(addition) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Ignoring addition as it was specified to be ignored.")
+			result.Ignore(addition.Path, "filecontent")
+			continue
+		}
+		for _, param := range httpParams(document) {
+			if !hd.isSecret(param) || len(ignoreConfig.reportableFindings(HTTPFixtureDetectorName, addition.Path, []string{param.value}, result)) == 0 {
+				continue
+			}
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+				"param":    param.name,
+			}).Info("Failing file as it records a secret in an HTTP interaction.")
+			message := fmt.Sprintf("Expected HTTP fixture to not to contain high entropy %s such as: %s=%s", param.kind, param.name, param.value)
+			result.FailAt(addition.Path, "filecontent", message, addition.Commits, HighSeverity, findingAssignedTo(addition.Data, param.value, param.name, "value"))
+		}
+	}
+}
+
+func (hd *HTTPFixtureDetector) isSecret(param httpParam) bool {
+	name := strings.ToLower(param.name)
+	if benignHTTPParams[name] || strings.HasPrefix(name, "utm_") || len(param.value) < minHTTPFixtureSecretLength || oauthPlaceholderPattern.MatchString(param.value) {
+		return false
+	}
+	return hd.entropy.GetShannonEntropy(param.value, httpFixtureEntropyChars) > httpFixtureEntropyThreshold
+}
+
+//httpFixtureDocument parses the HAR files, and the JSON and YAML cassettes that record HTTP interactions, answering false for any other file
+func httpFixtureDocument(addition git_repo.Addition) (map[string]interface{}, bool) {
+	var document interface{}
+	extension := strings.ToLower(path.Ext(string(addition.Name)))
+	switch extension {
+	case ".har", ".json":
+		if json.Unmarshal(addition.Data, &document) != nil {
+			return nil, false
+		}
+	case ".yml", ".yaml":
+		if yaml.Unmarshal(addition.Data, &document) != nil {
+			return nil, false
+		}
+		document = withStringKeys(document)
+	default:
+		return nil, false
+	}
+	root, isMap := document.(map[string]interface{})
+	if !isMap {
+		return nil, false
+	}
+	if extension == ".har" {
+		return root, true
+	}
+	for _, key := range httpFixtureRecordKeys {
+		if _, ok := root[key]; ok {
+			return root, true
+		}
+	}
+	return nil, false
+}
+
+//withStringKeys converts the maps read from YAML, which may have keys of any type, to maps with string keys like the ones read from JSON
+func withStringKeys(node interface{}) interface{} {
+	switch node := node.(type) {
+	case map[interface{}]interface{}:
+		result := map[string]interface{}{}
+		for key, value := range node {
+			result[fmt.Sprint(key)] = withStringKeys(value)
+		}
+		return result
+	case []interface{}:
+		for i, element := range node {
+			node[i] = withStringKeys(element)
+		}
+	}
+	return node
+}
+
+//httpParams walks the document, returning the query parameters of the recorded URLs and the headers and cookies of the requests and responses.
+//Headers are recorded either as lists of name and value pairs, as in HAR files, or as maps from the name to the values, as in VCR cassettes.
+func httpParams(document interface{}) []httpParam {
+	var params []httpParam
+	switch node := document.(type) {
+	case map[string]interface{}:
+		var keys []string
+		for key := range node {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			switch value := node[key]; strings.ToLower(key) {
+			case "url", "uri":
+				if url, isString := value.(string); isString {
+					params = append(params, queryParams(url)...)
+				}
+			case "querystring":
+				params = append(params, namedValues("query parameters", value)...)
+			case "cookies":
+				params = append(params, namedValues("cookies", value)...)
+			case "headers":
+				for _, header := range namedValues("headers", value) {
+					params = append(params, fromHeader(header)...)
+				}
+			default:
+				params = append(params, httpParams(value)...)
+			}
+		}
+	case []interface{}:
+		for _, element := range node {
+			params = append(params, httpParams(element)...)
+		}
+	}
+	return params
+}
+
+//queryParams returns the parameters of the query of the URL as they are written in it, without decoding them, so that they can be located in the file
+func queryParams(url string) []httpParam {
+	var params []httpParam
+	if !strings.Contains(url, "?") {
+		return params
+	}
+	query := url[strings.Index(url, "?")+1:]
+	if fragment := strings.Index(query, "#"); fragment != -1 {
+		query = query[:fragment]
+	}
+	for _, pair := range strings.Split(query, "&") {
+		if parts := strings.SplitN(pair, "=", 2); len(parts) == 2 {
+			params = append(params, httpParam{"query parameters", parts[0], parts[1]})
+		}
+	}
+	return params
+}
+
+//namedValues returns the values of a list of name and value pairs, or of a map from the names to one or more values
+func namedValues(kind string, node interface{}) []httpParam {
+	var params []httpParam
+	switch node := node.(type) {
+	case []interface{}:
+		for _, element := range node {
+			pair, isMap := element.(map[string]interface{})
+			if !isMap {
+				continue
+			}
+			name, _ := pair["name"].(string)
+			value, _ := pair["value"].(string)
+			params = append(params, httpParam{kind, name, value})
+		}
+	case map[string]interface{}:
+		var names []string
+		for name := range node {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			switch value := node[name].(type) {
+			case string:
+				params = append(params, httpParam{kind, name, value})
+			case []interface{}:
+				for _, element := range value {
+					if value, isString := element.(string); isString {
+						params = append(params, httpParam{kind, name, value})
+					}
+				}
+			}
+		}
+	}
+	return params
+}
+
+//fromHeader splits the Cookie and Set-Cookie headers into their cookies, and leaves out the scheme of the Authorization headers
+func fromHeader(header httpParam) []httpParam {
+	switch strings.ToLower(header.name) {
+	case "cookie", "set-cookie":
+		var cookies []httpParam
+		for i, cookie := range strings.Split(header.value, ";") {
+			parts := strings.SplitN(strings.TrimSpace(cookie), "=", 2)
+			if len(parts) == 2 && (i == 0 || strings.ToLower(header.name) == "cookie") {
+				cookies = append(cookies, httpParam{"cookies", parts[0], parts[1]})
+			}
+		}
+		return cookies
+	case "authorization", "proxy-authorization":
+		if fields := strings.Fields(header.value); len(fields) == 2 {
+			header.value = fields[1]
+		}
+	}
+	return []httpParam{header}
+}
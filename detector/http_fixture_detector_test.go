@@ -0,0 +1,133 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sessionCookieHAR = `{
+  "log": {
+    "entries": [
+      {
+        "request": {
+          "method": "GET",
+          "url": "https://example.com/orders?page=2&sort=date",
+          "headers": [
+            {"name": "Accept", "value": "application/json"},
+            {"name": "Cookie", "value": "theme=dark; sessionid=Qm9Lx2Vr8TzPq4Nw7Ks1Yh6Dj3Fa"}
+          ],
+          "cookies": [
+            {"name": "theme", "value": "dark"},
+            {"name": "sessionid", "value": "Qm9Lx2Vr8TzPq4Nw7Ks1Yh6Dj3Fa"}
+          ]
+        }
+      }
+    ]
+  }
+}`
+
+func TestShouldFlagASessionCookieOfAHARFile(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("fixtures/orders.har", []byte(sessionCookieHAR))}
+
+	NewHTTPFixtureDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.True(t, results.HasFailures(), "Expected the session cookie to be flagged")
+	failures := results.GetFailures("fixtures/orders.har")
+	assert.Len(t, failures, 1, "Expected the session cookie recorded in both the cookies and the Cookie header to be reported once")
+	assert.Equal(t, "Expected HTTP fixture to not to contain high entropy cookies such as: sessionid=Qm9Lx2Vr8TzPq4Nw7Ks1Yh6Dj3Fa", failures[0].Message)
+	assert.Equal(t, HighSeverity, failures[0].Severity)
+	assert.Equal(t, 10, failures[0].Line)
+}
+
+func TestShouldNotFlagAHARFileWithOnlyBenignParams(t *testing.T) {
+	har := `{"log": {"entries": [{
+  "request": {
+    "url": "https://example.com/search?q=talisman&page=3&utm_campaign=Xk82JdQ0aLm3Pz7Rt5Vw",
+    "headers": [
+      {"name": "User-Agent", "value": "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36"},
+      {"name": "If-None-Match", "value": "W/\"5e8f1c2a9b7d4e3f6a0b1c2d\""}
+    ],
+    "queryString": [{"name": "q", "value": "talisman"}, {"name": "page", "value": "3"}]
+  },
+  "response": {
+    "headers": [{"name": "X-Request-Id", "value": "7f3a9c2e-1b4d-4e8f-a6c5-0d9b8e7f6a5c"}, {"name": "Content-Type", "value": "text/html"}]
+  }
+}]}}`
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("search.har", []byte(har))}
+
+	NewHTTPFixtureDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasFailures(), "Expected benign params to not be flagged")
+}
+
+func TestShouldFlagTheTokensOfAVCRCassette(t *testing.T) {
+	cassette := `---
+http_interactions:
+- request:
+    method: get
+    uri: https://api.example.com/v1/users?api_key=9fK2mQ7xLp4ZtR8vWc3N&per_page=50
+    headers:
+      Authorization:
+      - Bearer eyJhbGciOiJIUzI1NiJ9.c3ViOjEyMzQ1Njc4OTA.Zk4Lr9
+      Accept:
+      - application/json
+  response:
+    headers:
+      Set-Cookie:
+      - _session=<SESSION>; path=/; HttpOnly
+`
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("spec/cassettes/users.yml", []byte(cassette))}
+
+	NewHTTPFixtureDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	failures := results.GetFailures("spec/cassettes/users.yml")
+	assert.Len(t, failures, 2, "Expected the bearer token and the api key to be flagged, but not the filtered cookie")
+	assert.Equal(t, "Expected HTTP fixture to not to contain high entropy headers such as: Authorization=eyJhbGciOiJIUzI1NiJ9.c3ViOjEyMzQ1Njc4OTA.Zk4Lr9", failures[0].Message)
+	assert.Equal(t, "Expected HTTP fixture to not to contain high entropy query parameters such as: api_key=9fK2mQ7xLp4ZtR8vWc3N", failures[1].Message)
+}
+
+func TestShouldNotScanJSONAndYAMLFilesThatAreNotCassettes(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{
+		git_repo.NewAddition("config.yml", []byte("headers:\n  X-Api-Key: 9fK2mQ7xLp4ZtR8vWc3N\n")),
+		git_repo.NewAddition("links.json", []byte(`{"url": "https://example.com/?key=9fK2mQ7xLp4ZtR8vWc3N"}`)),
+	}
+
+	NewHTTPFixtureDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasFailures(), "Expected files that do not record HTTP interactions to be left to the other detectors")
+}
+
+func TestShouldNotFlagAllowedHTTPFixtureSecrets(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("fixtures/orders.har", []byte(sessionCookieHAR))}
+	ignoreConfig := TalismanRCIgnore{Detectors: map[string]DetectorConfig{HTTPFixtureDetectorName: {AllowedPatterns: []string{"Qm9Lx2Vr8"}}}}
+
+	NewHTTPFixtureDetector().Test(additions, ignoreConfig, results)
+
+	assert.False(t, results.HasFailures(), "Expected the allowed session cookie to not be flagged")
+}
+
+func TestShouldReportTheRecordedSecretAtTheParamItIsAssignedTo(t *testing.T) {
+	har := `{"log": {"comment": "replays Qm9Lx2Vr8TzPq4Nw7Ks1Yh6Dj3Fa", "entries": [{
+  "request": {
+    "cookies": [
+      {"name": "sessionid", "value": "Qm9Lx2Vr8TzPq4Nw7Ks1Yh6Dj3Fa"}
+    ]
+  }
+}]}}`
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("fixtures/orders.har", []byte(har))}
+
+	NewHTTPFixtureDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	if assert.Len(t, results.GetFailures("fixtures/orders.har"), 1) {
+		assert.Equal(t, 4, results.GetFailures("fixtures/orders.har")[0].Line)
+	}
+}
@@ -1,11 +1,14 @@
 package detector
 
 import (
+	"fmt"
 	"gopkg.in/yaml.v2"
 	"log"
+	"path"
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 
 	"talisman/git_repo"
 )
@@ -19,8 +22,17 @@ const (
 
 	//DefaultRCFileName represents the name of default file in which all the ignore patterns are configured in new version
 	DefaultRCFileName string = ".talismanrc"
+
+	//ExpiryDateFormat represents the format of the expiry date of a file ignore
+	ExpiryDateFormat string = "2006-01-02"
+
+	//DefaultIgnoreFileName represents the name of the default file in which ignore patterns were configured in the legacy version
+	DefaultIgnoreFileName string = ".talismanignore"
 )
 
+//allDetectors lists the names of the detectors that a legacy ignore without any ignore:detector comment applies to
+var allDetectors = []string{"filename", "filecontent", "filesize"}
+
 //Ignores represents a set of patterns that have been configured to be ignored by the Detectors.
 //Detectors are expected to honor these ignores.
 type Ignores struct {
@@ -37,27 +49,267 @@ type FileIgnoreConfig struct {
 	FileName        string `yaml:"filename"`
 	Checksum        string `yaml:"checksum"`
 	IgnoreDetectors []string `yaml:"ignore_detectors"`
+	Expires         string `yaml:"expires,omitempty"`
+	Comment         string `yaml:"comment,omitempty"`
+	source          string
+	baseDirectory   string
 }
 
 type ScopeConfig struct {
 	ScopeName string `yaml:"scope"`
+	source    string
+}
+
+//IsExpired answers true if the ignore has an expiry date and that day has passed at the given time
+//An ignore stays in effect until the end of the day it expires on. Unparsable expiry dates never expire, as they are reported when the config is read.
+func (i FileIgnoreConfig) IsExpired(now time.Time) bool {
+	if isEmptyString(i.Expires) {
+		return false
+	}
+	expiry, err := time.ParseInLocation(ExpiryDateFormat, strings.TrimSpace(i.Expires), now.Location())
+	if err != nil {
+		return false
+	}
+	return !now.Before(expiry.AddDate(0, 0, 1))
+}
+
+//ExpiryDatesError returns an error for the first file ignore of the config whose expiry date is not in the format YYYY-MM-DD
+func (ignore TalismanRCIgnore) ExpiryDatesError() error {
+	for _, fileIgnoreConfig := range ignore.FileIgnoreConfig {
+		if isEmptyString(fileIgnoreConfig.Expires) {
+			continue
+		}
+		if _, err := time.Parse(ExpiryDateFormat, strings.TrimSpace(fileIgnoreConfig.Expires)); err != nil {
+			return fmt.Errorf("invalid expires: unable to parse the expiry date %q of the ignore for %s, expected the format YYYY-MM-DD", fileIgnoreConfig.Expires, fileIgnoreConfig.FileName)
+		}
+	}
+	return nil
 }
 
+//Source returns the name of the config file the ignore was read from
+func (i FileIgnoreConfig) Source() string {
+	return i.source
+}
+
+//Matches answers true if the file name of the ignore matches the addition.
+//The file name of an ignore read from a nested config is relative to the directory of that config,
+//so it only matches the additions inside that directory, as if the directory was the root of the repository.
+func (i FileIgnoreConfig) Matches(addition git_repo.Addition) bool {
+	if i.baseDirectory == "" {
+		return addition.Matches(i.FileName)
+	}
+	relativePath := strings.TrimPrefix(string(addition.Path), i.baseDirectory+"/")
+	if relativePath == string(addition.Path) {
+		return false
+	}
+	return git_repo.NewAddition(relativePath, addition.Data).Matches(i.FileName)
+}
+
+//filePath returns the path of the file name of the ignore relative to the root of the repository
+func (i FileIgnoreConfig) filePath() string {
+	if i.baseDirectory == "" {
+		return i.FileName
+	}
+	return path.Join(i.baseDirectory, i.FileName)
+}
+
+//Source returns the name of the config file the scope was read from
+func (s ScopeConfig) Source() string {
+	return s.source
+}
+
+
 type TalismanRCIgnore struct {
-	FileIgnoreConfig []FileIgnoreConfig `yaml:"fileignoreconfig"`
-	ScopeConfig      []ScopeConfig      `yaml:"scopeconfig"`
+	FileIgnoreConfig          []FileIgnoreConfig        `yaml:"fileignoreconfig"`
+	ScopeConfig               []ScopeConfig             `yaml:"scopeconfig"`
+	MaxLineLength             int                       `yaml:"max_line_length,omitempty"`
+	LongLineAction            string                    `yaml:"long_line_action,omitempty"`
+	Archive                   []FileIgnoreConfig        `yaml:"archive,omitempty"`
+	AllowedPatterns           []string                  `yaml:"allowed_patterns,omitempty"`
+	Detectors                 map[string]DetectorConfig `yaml:"detectors,omitempty"`
+	Fixtures                  FixtureConfig             `yaml:"fixtures,omitempty"`
+	MarkdownFencesOnly        bool                      `yaml:"markdown_fences_only,omitempty"`
+	Enforce                   *bool                     `yaml:"enforce,omitempty"`
+	Suppress                  []string                  `yaml:"suppress,omitempty"`
+	MaxFilesPerRule           int                       `yaml:"max_files_per_rule,omitempty"`
+	DetectorExtensionIncludes map[string][]string       `yaml:"detector_extension_includes,omitempty"`
+	MergeAdjacentFindings     *bool                     `yaml:"merge_adjacent_findings,omitempty"`
+	SeverityActionConfig      map[string]string         `yaml:"severity_actions,omitempty"`
+	ContextDetector           ContextDetectorConfig     `yaml:"context_detector,omitempty"`
+	CriticalPaths             []string                  `yaml:"critical_paths,omitempty"`
+	ruleSources               map[string]string
 }
 
 func (ignore TalismanRCIgnore) IsEmpty() bool {
 	return reflect.DeepEqual(TalismanRCIgnore{}, ignore)
 }
 
-func ReadConfigFromRCFile(repoFileRead func(string) ([]byte, error)) TalismanRCIgnore {
-	fileContents, error := repoFileRead(DefaultRCFileName)
+//MergesAdjacentFindings answers true unless the config sets merge_adjacent_findings: false, in which case the encoded texts on adjacent lines,
+//such as the lines of a PEM block, are reported as one finding per line instead of a single finding spanning the lines
+func (ignore TalismanRCIgnore) MergesAdjacentFindings() bool {
+	return ignore.MergeAdjacentFindings == nil || *ignore.MergeAdjacentFindings
+}
+
+//IsEnforced answers true unless the config sets enforce: false, in which case the findings are reported without failing the run
+func (ignore TalismanRCIgnore) IsEnforced() bool {
+	return ignore.Enforce == nil || *ignore.Enforce
+}
+
+//ReadConfigFromRCFile reads the .talismanrc from the given source
+func ReadConfigFromRCFile(source ConfigSource) TalismanRCIgnore {
+	fileContents, error := source.ReadConfig(DefaultRCFileName)
 	if error != nil {
 		panic(error)
 	}
-	return NewTalismanRCIgnore(fileContents)
+	return NewTalismanRCIgnore(fileContents).WithSource(DefaultRCFileName)
+}
+
+//ReadConfigChain reads the given config files and merges them in order, so that the later configs override the scalar settings of the earlier ones
+//while the ignores, scopes and allowed patterns of all of them are applied
+func ReadConfigChain(source ConfigSource, fileNames []string) TalismanRCIgnore {
+	result := TalismanRCIgnore{}
+	for _, fileName := range fileNames {
+		fileContents, err := source.ReadConfig(fileName)
+		if err != nil {
+			panic(err)
+		}
+		result = result.MergeWith(NewTalismanRCIgnore(fileContents).WithSource(fileName))
+	}
+	return result
+}
+
+//WithSource records the given config file name as the source of all the ignores, scopes, allowed patterns, suppress expressions
+//and severity actions that do not have one yet
+func (ignore TalismanRCIgnore) WithSource(source string) TalismanRCIgnore {
+	result := ignore
+	result.FileIgnoreConfig = nil
+	result.ScopeConfig = nil
+	result.ruleSources = nil
+	for key, ruleSource := range ignore.ruleSources {
+		result.ruleSources = withRuleSource(result.ruleSources, key, ruleSource)
+	}
+	for _, key := range ignore.ruleKeys() {
+		if _, ok := result.ruleSources[key]; !ok {
+			result.ruleSources = withRuleSource(result.ruleSources, key, source)
+		}
+	}
+	for _, fileIgnoreConfig := range ignore.FileIgnoreConfig {
+		if fileIgnoreConfig.source == "" {
+			fileIgnoreConfig.source = source
+		}
+		result.FileIgnoreConfig = append(result.FileIgnoreConfig, fileIgnoreConfig)
+	}
+	for _, scopeConfig := range ignore.ScopeConfig {
+		if scopeConfig.source == "" {
+			scopeConfig.source = source
+		}
+		result.ScopeConfig = append(result.ScopeConfig, scopeConfig)
+	}
+	return result
+}
+
+//WithBaseDirectory resolves the file names of the ignores relative to the given directory of the repository,
+//which is where a nested config applying to that directory only is read from
+func (ignore TalismanRCIgnore) WithBaseDirectory(directory string) TalismanRCIgnore {
+	result := ignore
+	result.FileIgnoreConfig = nil
+	directory = path.Clean(directory)
+	if directory == "." {
+		directory = ""
+	}
+	for _, fileIgnoreConfig := range ignore.FileIgnoreConfig {
+		fileIgnoreConfig.baseDirectory = directory
+		result.FileIgnoreConfig = append(result.FileIgnoreConfig, fileIgnoreConfig)
+	}
+	return result
+}
+
+//MergeWith returns the union of the ignores and scopes of both configs, with those of the other config applied after the current ones
+func (ignore TalismanRCIgnore) MergeWith(other TalismanRCIgnore) TalismanRCIgnore {
+	result := TalismanRCIgnore{}
+	result.FileIgnoreConfig = append(append(result.FileIgnoreConfig, ignore.FileIgnoreConfig...), other.FileIgnoreConfig...)
+	result.ScopeConfig = append(append(result.ScopeConfig, ignore.ScopeConfig...), other.ScopeConfig...)
+	result.Archive = append(append(result.Archive, ignore.Archive...), other.Archive...)
+	result.AllowedPatterns = append(append(result.AllowedPatterns, ignore.AllowedPatterns...), other.AllowedPatterns...)
+	result.Suppress = append(append(result.Suppress, ignore.Suppress...), other.Suppress...)
+	result.CriticalPaths = append(append(result.CriticalPaths, ignore.CriticalPaths...), other.CriticalPaths...)
+	result.Detectors = mergeDetectorConfigs(ignore.Detectors, other.Detectors)
+	result.DetectorExtensionIncludes = mergeExtensionIncludes(ignore.DetectorExtensionIncludes, other.DetectorExtensionIncludes)
+	result.Fixtures = ignore.Fixtures.mergeWith(other.Fixtures)
+	result.MaxLineLength = ignore.MaxLineLength
+	if other.MaxLineLength != 0 {
+		result.MaxLineLength = other.MaxLineLength
+	}
+	result.MaxFilesPerRule = ignore.MaxFilesPerRule
+	if other.MaxFilesPerRule != 0 {
+		result.MaxFilesPerRule = other.MaxFilesPerRule
+	}
+	result.LongLineAction = ignore.LongLineAction
+	if other.LongLineAction != "" {
+		result.LongLineAction = other.LongLineAction
+	}
+	result.MarkdownFencesOnly = ignore.MarkdownFencesOnly || other.MarkdownFencesOnly
+	result.ContextDetector = ignore.ContextDetector.mergeWith(other.ContextDetector)
+	result.SeverityActionConfig = mergeSeverityActions(ignore.SeverityActionConfig, other.SeverityActionConfig)
+	result.MergeAdjacentFindings = ignore.MergeAdjacentFindings
+	if other.MergeAdjacentFindings != nil {
+		result.MergeAdjacentFindings = other.MergeAdjacentFindings
+	}
+	result.Enforce = ignore.Enforce
+	if other.Enforce != nil {
+		result.Enforce = other.Enforce
+	}
+	for _, sources := range []map[string]string{ignore.ruleSources, other.ruleSources} {
+		for key, source := range sources {
+			result.ruleSources = withRuleSource(result.ruleSources, key, source)
+		}
+	}
+	return result
+}
+
+//ruleKeys returns the keys by which the sources of the allowed patterns, suppress expressions and severity actions are recorded
+func (ignore TalismanRCIgnore) ruleKeys() []string {
+	var keys []string
+	for _, pattern := range ignore.AllowedPatterns {
+		keys = append(keys, allowedPatternKey("", pattern))
+	}
+	for name, detectorConfig := range ignore.Detectors {
+		for _, pattern := range detectorConfig.AllowedPatterns {
+			keys = append(keys, allowedPatternKey(name, pattern))
+		}
+	}
+	for _, expression := range ignore.Suppress {
+		keys = append(keys, suppressKey(expression))
+	}
+	for severity, action := range ignore.SeverityActionConfig {
+		keys = append(keys, severityActionKey(severity, action))
+	}
+	return keys
+}
+
+//ruleSource returns the name of the config file the rule with the given key was read from
+func (ignore TalismanRCIgnore) ruleSource(key string) string {
+	return ignore.ruleSources[key]
+}
+
+func allowedPatternKey(detectorName string, pattern string) string {
+	return "allowed_patterns:" + detectorName + ":" + pattern
+}
+
+func suppressKey(expression string) string {
+	return "suppress:" + expression
+}
+
+func severityActionKey(severity string, action string) string {
+	return "severity_actions:" + severity + ":" + action
+}
+
+func withRuleSource(sources map[string]string, key string, source string) map[string]string {
+	if sources == nil {
+		sources = map[string]string{}
+	}
+	sources[key] = source
+	return sources
 }
 
 
@@ -72,6 +324,41 @@ func NewTalismanRCIgnore(fileContents []byte) (TalismanRCIgnore) {
 	return talismanRCIgnore
 }
 
+//ReadIgnoresFromFile reads the legacy ignore file with the given name, falling back to the DefaultIgnoreFileName when no name is given
+//The ignores are returned as a TalismanRCIgnore, so that they can be merged with the ignores from the .talismanrc
+func ReadIgnoresFromFile(source ConfigSource, fileName string) TalismanRCIgnore {
+	if isEmptyString(fileName) {
+		fileName = DefaultIgnoreFileName
+	}
+	fileContents, err := source.ReadConfig(fileName)
+	if err != nil {
+		panic(err)
+	}
+	return NewIgnores(strings.Split(string(fileContents), "\n")...).AsTalismanRCIgnore(fileName)
+}
+
+//AsTalismanRCIgnore converts the legacy ignores into their .talismanrc equivalent, recording the given file name as their source
+//A pattern without an ignore:detector comment ignores all the detectors, as it did in the legacy version, and the comment is kept as the comment of the file ignore
+func (i Ignores) AsTalismanRCIgnore(source string) TalismanRCIgnore {
+	result := TalismanRCIgnore{}
+	for _, ignore := range i.patterns {
+		if isEmptyString(ignore.pattern) {
+			continue
+		}
+		ignoredDetectors := ignore.ignoredDetectors
+		if len(ignoredDetectors) == 0 {
+			ignoredDetectors = allDetectors
+		}
+		result.FileIgnoreConfig = append(result.FileIgnoreConfig, FileIgnoreConfig{
+			FileName:        ignore.pattern,
+			IgnoreDetectors: ignoredDetectors,
+			Comment:         ignore.comment,
+			source:          source,
+		})
+	}
+	return result
+}
+
 func NewIgnore(pattern string, comment string) Ignore {
 	var ignoredDetectors []string
 	ignorePattern := regexp.MustCompile(IgnoreDetectorCommentPattern)
@@ -89,7 +376,24 @@ func NewIgnore(pattern string, comment string) Ignore {
 
 func (i FileIgnoreConfig) isEffective(detectorName string) bool {
 	return !isEmptyString(i.FileName) &&
-		contains(i.IgnoreDetectors, detectorName)
+		contains(i.ScopedDetectors(), detectorName) &&
+		!i.IsExpired(time.Now())
+}
+
+//ScopedDetectors returns the detectors that the ignore applies to. These are the ignore_detectors,
+//along with the detectors of a comment starting with ignore:detector1,detector2 as written in the legacy ignore file
+func (i FileIgnoreConfig) ScopedDetectors() []string {
+	match := regexp.MustCompile(IgnoreDetectorCommentPattern).FindStringSubmatch(strings.TrimSpace(i.Comment))
+	if match == nil {
+		return i.IgnoreDetectors
+	}
+	var detectors []string
+	for _, detector := range append(append(detectors, i.IgnoreDetectors...), strings.Split(match[1], ",")...) {
+		if !contains(detectors, detector) {
+			detectors = append(detectors, detector)
+		}
+	}
+	return detectors
 }
 
 
@@ -142,18 +446,25 @@ func IgnoreAdditionsByScope(additions []git_repo.Addition, rcConfigIgnores Talis
 }
 //Deny answers true if the Addition.Path is configured to be ignored and not checked by the detectors
 func (i TalismanRCIgnore) Deny(addition git_repo.Addition, detectorName string) bool {
-	result := false
-	for _, pattern := range i.effectiveRules(detectorName) {
-		result = result || addition.Matches(pattern)
+	_, denied := i.denyingRule(addition, detectorName)
+	return denied
+}
+
+//denyingRule returns the first effective file ignore that matches the addition, which is the rule recorded in the audit log for ignoring it
+func (i TalismanRCIgnore) denyingRule(addition git_repo.Addition, detectorName string) (FileIgnoreConfig, bool) {
+	for _, ignore := range i.effectiveRules(detectorName) {
+		if ignore.Matches(addition) {
+			return ignore, true
+		}
 	}
-	return result
+	return FileIgnoreConfig{}, false
 }
 
-func (i TalismanRCIgnore) effectiveRules(detectorName string) []string {
-	var result []string
+func (i TalismanRCIgnore) effectiveRules(detectorName string) []FileIgnoreConfig {
+	var result []FileIgnoreConfig
 	for _, ignore := range i.FileIgnoreConfig {
 		if ignore.isEffective(detectorName) {
-			result = append(result, ignore.FileName)
+			result = append(result, ignore)
 		}
 	}
 	return result
@@ -0,0 +1,123 @@
+package detector
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//ListIgnores returns a string documenting every file ignore, scope, allowed pattern, suppress expression and ignored severity of the config
+//as it will be applied, along with the config file it was read from. The file ignores that have expired are marked as such.
+func (ignore TalismanRCIgnore) ListIgnores() string {
+	ignoredSeverities := ignore.ignoredSeverities()
+	if len(ignore.FileIgnoreConfig) == 0 && len(ignore.ScopeConfig) == 0 && len(ignore.allowedPatternRows()) == 0 &&
+		len(ignore.Suppress) == 0 && len(ignoredSeverities) == 0 {
+		return "No ignores are configured\n"
+	}
+	var result string
+	if len(ignore.FileIgnoreConfig) > 0 {
+		var data [][]string
+		now := time.Now()
+		for _, fileIgnoreConfig := range ignore.FileIgnoreConfig {
+			data = append(data, []string{
+				fileIgnoreConfig.FileName,
+				listOrNone(fileIgnoreConfig.ScopedDetectors()),
+				valueOrNone(fileIgnoreConfig.Checksum),
+				expiryOf(fileIgnoreConfig, now),
+				valueOrNone(fileIgnoreConfig.Source()),
+			})
+		}
+		result = result + "File ignores:\n" + renderListing([]string{"File", "Ignored detectors", "Checksum", "Expires", "Source"}, data)
+	}
+	if len(ignore.ScopeConfig) > 0 {
+		var data [][]string
+		for _, scopeConfig := range ignore.ScopeConfig {
+			data = append(data, []string{scopeConfig.ScopeName, valueOrNone(scopeConfig.Source())})
+		}
+		result = result + "Scopes:\n" + renderListing([]string{"Scope", "Source"}, data)
+	}
+	if data := ignore.allowedPatternRows(); len(data) > 0 {
+		result = result + "Allowed patterns:\n" + renderListing([]string{"Pattern", "Detector", "Source"}, data)
+	}
+	if len(ignore.Suppress) > 0 {
+		var data [][]string
+		for _, expression := range ignore.Suppress {
+			data = append(data, []string{expression, valueOrNone(ignore.ruleSource(suppressKey(expression)))})
+		}
+		result = result + "Suppress expressions:\n" + renderListing([]string{"Expression", "Source"}, data)
+	}
+	if len(ignoredSeverities) > 0 {
+		var data [][]string
+		for _, severity := range ignoredSeverities {
+			data = append(data, []string{severity, valueOrNone(ignore.ruleSource(severityActionKey(severity, IgnoreAction)))})
+		}
+		result = result + "Ignored severities:\n" + renderListing([]string{"Severity", "Source"}, data)
+	}
+	return result
+}
+
+//allowedPatternRows lists the global allowed patterns, followed by those of each detector in the order of their names
+func (ignore TalismanRCIgnore) allowedPatternRows() [][]string {
+	var data [][]string
+	for _, pattern := range ignore.AllowedPatterns {
+		data = append(data, []string{pattern, "all", valueOrNone(ignore.ruleSource(allowedPatternKey("", pattern)))})
+	}
+	var names []string
+	for name := range ignore.Detectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, pattern := range ignore.Detectors[name].AllowedPatterns {
+			data = append(data, []string{pattern, name, valueOrNone(ignore.ruleSource(allowedPatternKey(name, pattern)))})
+		}
+	}
+	return data
+}
+
+//ignoredSeverities returns the severities whose findings the severity_actions ignore, from the lowest to the highest
+func (ignore TalismanRCIgnore) ignoredSeverities() []string {
+	var severities []string
+	for _, severity := range []Severity{LowSeverity, MediumSeverity, HighSeverity, CriticalSeverity} {
+		for name, action := range ignore.SeverityActionConfig {
+			if parsed, err := SeverityFromString(name); err == nil && parsed == severity && action == IgnoreAction {
+				severities = append(severities, name)
+			}
+		}
+	}
+	return severities
+}
+
+func expiryOf(fileIgnoreConfig FileIgnoreConfig, now time.Time) string {
+	if fileIgnoreConfig.IsExpired(now) {
+		return fileIgnoreConfig.Expires + " (expired)"
+	}
+	return valueOrNone(fileIgnoreConfig.Expires)
+}
+
+func renderListing(header []string, data [][]string) string {
+	var buffer bytes.Buffer
+	table := tablewriter.NewWriter(&buffer)
+	table.SetHeader(header)
+	table.SetAutoWrapText(false)
+	table.AppendBulk(data)
+	table.Render()
+	return buffer.String()
+}
+
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ", ")
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,89 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const repoRCContents = `
+fileignoreconfig:
+- filename: danger.pem
+  checksum: 87139cc4d975333b25b6275f97680604add51b84eb8f4a3b9dcbbc652e6f27ac
+  ignore_detectors: [filename]
+scopeconfig:
+- scope: go
+`
+
+const otherRCContents = `
+fileignoreconfig:
+- filename: fixtures/
+  ignore_detectors: [filecontent, filesize]
+`
+
+func TestListingShouldShowRulesOfAllMergedSources(t *testing.T) {
+	repoConfig := NewTalismanRCIgnore([]byte(repoRCContents)).WithSource(".talismanrc")
+	otherConfig := NewTalismanRCIgnore([]byte(otherRCContents)).WithSource("other/.talismanrc")
+
+	listing := repoConfig.MergeWith(otherConfig).ListIgnores()
+
+	assert.Regexp(t, `danger\.pem +\| filename +\| 87139cc4d975333b25b6275f97680604add51b84eb8f4a3b9dcbbc652e6f27ac \| - +\| \.talismanrc`, listing)
+	assert.Regexp(t, `fixtures/ +\| filecontent, filesize +\| - +\| - +\| other/\.talismanrc`, listing)
+	assert.Regexp(t, `go +\| \.talismanrc`, listing)
+}
+
+func TestMergingShouldKeepTheSourceOfEachRule(t *testing.T) {
+	repoConfig := NewTalismanRCIgnore([]byte(repoRCContents)).WithSource(".talismanrc")
+	otherConfig := NewTalismanRCIgnore([]byte(otherRCContents)).WithSource("other/.talismanrc")
+
+	merged := repoConfig.MergeWith(otherConfig)
+
+	assert.Len(t, merged.FileIgnoreConfig, 2)
+	assert.Equal(t, ".talismanrc", merged.FileIgnoreConfig[0].Source())
+	assert.Equal(t, "other/.talismanrc", merged.FileIgnoreConfig[1].Source())
+}
+
+func TestListingShouldSayWhenNothingIsIgnored(t *testing.T) {
+	assert.Equal(t, "No ignores are configured\n", TalismanRCIgnore{}.ListIgnores())
+}
+
+const allowingRCContents = `
+allowed_patterns:
+- 'example\.com'
+detectors:
+  pattern:
+    allowed_patterns:
+    - 'password=changeme'
+suppress:
+- 'path matches "test/**"'
+severity_actions:
+  low: ignore
+  high: warn
+`
+
+func TestListingShouldShowTheAllowedPatternsSuppressExpressionsAndIgnoredSeverities(t *testing.T) {
+	repoConfig := NewTalismanRCIgnore([]byte(repoRCContents)).WithSource(".talismanrc")
+	otherConfig := NewTalismanRCIgnore([]byte(allowingRCContents)).WithSource("other/.talismanrc")
+
+	listing := repoConfig.MergeWith(otherConfig).ListIgnores()
+
+	assert.Regexp(t, `example\\\.com +\| all +\| other/\.talismanrc`, listing)
+	assert.Regexp(t, `password=changeme +\| pattern +\| other/\.talismanrc`, listing)
+	assert.Regexp(t, `path matches "test/\*\*" +\| other/\.talismanrc`, listing)
+	assert.Regexp(t, `low +\| other/\.talismanrc`, listing)
+	assert.NotContains(t, listing, "high", "Expected only the ignored severities to be listed")
+}
+
+func TestListingShouldShowAConfigWithOnlyAllowedPatterns(t *testing.T) {
+	listing := NewTalismanRCIgnore([]byte("allowed_patterns:\n- 'example\\.com'\n")).WithSource(".talismanrc").ListIgnores()
+
+	assert.NotEqual(t, "No ignores are configured\n", listing)
+	assert.Regexp(t, `example\\\.com +\| all +\| \.talismanrc`, listing)
+}
+
+func TestListingShouldMarkTheExpiredFileIgnores(t *testing.T) {
+	listing := NewTalismanRCIgnore([]byte("fileignoreconfig:\n- filename: old.pem\n  expires: 2020-01-31\n- filename: new.pem\n  expires: 2999-01-31\n")).ListIgnores()
+
+	assert.Regexp(t, `old\.pem +\| - +\| - +\| 2020-01-31 \(expired\) +\|`, listing)
+	assert.Regexp(t, `new\.pem +\| - +\| - +\| 2999-01-31 +\|`, listing)
+}
@@ -6,6 +6,7 @@ import (
 	"talisman/git_repo"
 
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
 )
 
 func TestShouldIgnoreEmptyLinesInTheFile(t *testing.T) {
@@ -167,3 +168,127 @@ func SingleIgnore(pattern string, comment string, ignoredDetectors ...string) Ig
 		ignoredDetectors: ignoredDetectors,
 	}}}
 }
+
+func TestShouldReadLegacyIgnoresFromACustomPath(t *testing.T) {
+	var requestedFileName string
+	readFile := func(fileName string) ([]byte, error) {
+		requestedFileName = fileName
+		return []byte("*.pem # ignore:filename\nfixtures/\n"), nil
+	}
+
+	ignores := ReadIgnoresFromFile(ConfigSourceFunc(readFile), "config/custom-ignores")
+
+	assert.Equal(t, "config/custom-ignores", requestedFileName)
+	assert.True(t, ignores.Deny(testAddition("danger.pem"), "filename"))
+	assert.False(t, ignores.Deny(testAddition("danger.pem"), "filecontent"), "Expected the ignore:filename comment to scope the ignore")
+	assert.True(t, ignores.Deny(testAddition("fixtures/data.json"), "filecontent"), "Expected an unscoped pattern to ignore all detectors")
+	assert.Equal(t, "config/custom-ignores", ignores.FileIgnoreConfig[0].Source())
+}
+
+func TestShouldMergeTheConfigChainInOrder(t *testing.T) {
+	configs := map[string]string{
+		"org.rc":      "fileignoreconfig:\n- filename: org.pem\n  ignore_detectors: [filename]\nmax_line_length: 1000\nlong_line_action: skip\n",
+		"team.rc":     "fileignoreconfig:\n- filename: team.pem\n  ignore_detectors: [filename]\nmax_line_length: 500\n",
+		".talismanrc": "scopeconfig:\n- scope: go\n",
+	}
+	readFile := func(fileName string) ([]byte, error) {
+		return []byte(configs[fileName]), nil
+	}
+
+	config := ReadConfigChain(ConfigSourceFunc(readFile), []string{"org.rc", "team.rc", ".talismanrc"})
+
+	assert.True(t, config.Deny(testAddition("org.pem"), "filename"), "Expected the ignores of all the configs to be applied")
+	assert.True(t, config.Deny(testAddition("team.pem"), "filename"), "Expected the ignores of all the configs to be applied")
+	assert.Equal(t, "org.rc", config.FileIgnoreConfig[0].Source())
+	assert.Equal(t, "team.rc", config.FileIgnoreConfig[1].Source())
+	assert.Equal(t, ".talismanrc", config.ScopeConfig[0].Source())
+	assert.Equal(t, 500, config.MaxLineLength, "Expected the later config to override the scalar settings")
+	assert.Equal(t, SkipLongLines, config.LongLineAction, "Expected the settings the later configs don't set to be kept")
+}
+
+func TestShouldReadLegacyIgnoresFromTheDefaultFileWhenNoPathIsGiven(t *testing.T) {
+	var requestedFileName string
+	readFile := func(fileName string) ([]byte, error) {
+		requestedFileName = fileName
+		return []byte{}, nil
+	}
+
+	ReadIgnoresFromFile(ConfigSourceFunc(readFile), "")
+
+	assert.Equal(t, DefaultIgnoreFileName, requestedFileName)
+}
+
+func TestShouldBeEnforcedUnlessTurnedOff(t *testing.T) {
+	assert.True(t, NewTalismanRCIgnore([]byte("")).IsEnforced(), "Expected the config to be enforced by default")
+	assert.True(t, NewTalismanRCIgnore([]byte("enforce: true")).IsEnforced())
+	assert.False(t, NewTalismanRCIgnore([]byte("enforce: false")).IsEnforced())
+
+	advisory := NewTalismanRCIgnore([]byte("enforce: false"))
+	assert.False(t, advisory.MergeWith(TalismanRCIgnore{}).IsEnforced(), "Expected a config without enforce to keep the earlier setting")
+	assert.True(t, advisory.MergeWith(NewTalismanRCIgnore([]byte("enforce: true"))).IsEnforced(), "Expected the later config to override the earlier setting")
+}
+
+func TestShouldScopeIgnoresByTheIgnoreCommentOfTheTalismanRC(t *testing.T) {
+	ignores := NewTalismanRCIgnore([]byte(`fileignoreconfig:
+- filename: test/vectors.pem
+  comment: ignore:filecontent,filesize the test vectors of the parser
+- filename: docs/setup.md
+  ignore_detectors: [filename]
+  comment: reviewed by the security team
+`))
+
+	assert.True(t, ignores.Deny(testAddition("test/vectors.pem"), "filecontent"))
+	assert.True(t, ignores.Deny(testAddition("test/vectors.pem"), "filesize"))
+	assert.False(t, ignores.Deny(testAddition("test/vectors.pem"), "filename"), "Expected the ignore:filecontent,filesize comment to scope the ignore")
+	assert.Equal(t, []string{"filecontent", "filesize"}, ignores.FileIgnoreConfig[0].ScopedDetectors())
+	assert.Equal(t, []string{"filename"}, ignores.FileIgnoreConfig[1].ScopedDetectors(), "Expected a free-form comment to not scope the ignore")
+}
+
+func TestMigratedLegacyIgnoresShouldBehaveIdenticallyInBothFormats(t *testing.T) {
+	legacy := NewIgnores(
+		"*.pem # ignore:filename,filecontent kept for the parser tests",
+		"fixtures/ # generated by the fixture builder",
+		"notes.txt # ignore:filesize",
+	).AsTalismanRCIgnore(DefaultIgnoreFileName)
+	migratedContents, err := yaml.Marshal(legacy)
+	assert.NoError(t, err)
+	migrated := NewTalismanRCIgnore(migratedContents)
+
+	assert.Equal(t, "ignore:filename,filecontent kept for the parser tests", migrated.FileIgnoreConfig[0].Comment, "Expected the legacy comment to be kept")
+	for _, filePath := range []string{"danger.pem", "fixtures/data.json", "notes.txt", "main.go"} {
+		for _, detectorName := range []string{"filename", "filecontent", "filesize"} {
+			assert.Equal(t, legacy.Deny(testAddition(filePath), detectorName), migrated.Deny(testAddition(filePath), detectorName), "Expected %s to be treated identically for %s", filePath, detectorName)
+		}
+	}
+	assert.False(t, migrated.Deny(testAddition("danger.pem"), "filesize"))
+	assert.True(t, migrated.Deny(testAddition("fixtures/data.json"), "filesize"))
+}
+
+func TestNestedConfigShouldResolveItsIgnoresFromItsOwnDirectory(t *testing.T) {
+	nested := NewTalismanRCIgnore([]byte(`
+fileignoreconfig:
+- filename: '*.key'
+  ignore_detectors: [filename]
+- filename: fixtures/token.txt
+  ignore_detectors: [filecontent]
+`)).WithBaseDirectory("services/api")
+
+	assert.True(t, nested.Deny(testAddition("services/api/server.key"), "filename"))
+	assert.True(t, nested.Deny(testAddition("services/api/certs/server.key"), "filename"))
+	assert.False(t, nested.Deny(testAddition("server.key"), "filename"), "Expected the nested config to not ignore a same-named file at the root")
+	assert.False(t, nested.Deny(testAddition("services/web/server.key"), "filename"), "Expected the nested config to not ignore a same-named file in another directory")
+	assert.False(t, nested.Deny(testAddition("services/api-gateway/server.key"), "filename"), "Expected the nested config to not ignore a file in a directory sharing its prefix")
+	assert.True(t, nested.Deny(testAddition("services/api/fixtures/token.txt"), "filecontent"))
+	assert.False(t, nested.Deny(testAddition("fixtures/token.txt"), "filecontent"))
+}
+
+func TestNestedConfigShouldKeepItsDirectoryWhenMergedWithTheRootConfig(t *testing.T) {
+	root := NewTalismanRCIgnore([]byte("fileignoreconfig:\n- filename: root.pem\n  ignore_detectors: [filename]\n"))
+	nested := NewTalismanRCIgnore([]byte("fileignoreconfig:\n- filename: '*.key'\n  ignore_detectors: [filename]\n")).WithBaseDirectory("./services/api/")
+	merged := root.MergeWith(nested)
+
+	assert.True(t, merged.Deny(testAddition("root.pem"), "filename"))
+	assert.True(t, merged.Deny(testAddition("services/api/server.key"), "filename"))
+	assert.False(t, merged.Deny(testAddition("server.key"), "filename"))
+	assert.Equal(t, "services/api/*.key", merged.FileIgnoreConfig[1].filePath())
+}
@@ -0,0 +1,53 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//keyFileNamePattern matches the names of files that usually hold private keys, such as id_rsa, server.key or release.p12
+const keyFileNamePattern = `(?:id_(?:rsa|dsa|ecdsa|ed25519)|[\w.-]+\.(?:pem|key|p12|pfx|ppk|jks|keystore))\b`
+
+//keyPathPattern matches absolute Unix and Windows paths, including home directory paths and escaped backslashes, to key-like files
+var keyPathPattern = regexp.MustCompile(`(?:^|[\s"'=(,])((?:~|/[\w.@-]+)(?:/[\w.@ -]+?)*/` + keyFileNamePattern + `|[A-Za-z]:(?:\\{1,2}[\w.@ -]+?)*\\{1,2}` + keyFileNamePattern + `)`)
+
+//KeyPathDetector flags hardcoded absolute paths to private keys. The key itself is not committed, but the path ties the code to the setup of a single machine.
+//It is an opt-in detector, which only runs when enabled under detectors.keypath in the .talismanrc.
+type KeyPathDetector struct{}
+
+//NewKeyPathDetector returns a KeyPathDetector
+func NewKeyPathDetector() *KeyPathDetector {
+	return &KeyPathDetector{}
+}
+
+func (kd *KeyPathDetector) isEnabled(ignoreConfig TalismanRCIgnore) bool {
+	return ignoreConfig.Detectors[KeyPathDetectorName].Enabled
+}
+
+//Test tests the contents of the Additions to ensure that they don't refer to private keys by absolute paths
+func (kd *KeyPathDetector) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	for _, addition := range additions {
+		if ignoreConfig.Deny(addition, "filecontent") || cc.IsScanNotRequired(addition) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Ignoring addition as it was specified to be ignored.")
+			result.Ignore(addition.Path, "filecontent")
+			continue
+		}
+		var paths []string
+		for _, match := range keyPathPattern.FindAllStringSubmatch(string(addition.Data), -1) {
+			paths = append(paths, match[1])
+		}
+		for _, keyPath := range ignoreConfig.reportableFindings(KeyPathDetectorName, addition.Path, paths, result) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Failing file as it refers to a private key by an absolute path.")
+			result.FailAt(addition.Path, "filecontent", fmt.Sprintf("Expected file to not to contain hardcoded paths to private keys such as: %s", keyPath), addition.Commits, LowSeverity, findingIn(addition.Data, keyPath))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var keyPathEnabled = NewTalismanRCIgnore([]byte("detectors:\n  keypath:\n    enabled: true\n"))
+
+func TestShouldFlagHardcodedPathsToKeysWhenEnabled(t *testing.T) {
+	for line, keyPath := range map[string]string{
+		`ssh.connect(host, key_filename="/home/deploy/.ssh/id_rsa")`: "/home/deploy/.ssh/id_rsa",
+		`key: ~/.ssh/id_ed25519`:                                     "~/.ssh/id_ed25519",
+		`cert = open('/etc/ssl/private/server.key')`:                 "/etc/ssl/private/server.key",
+		`string keyPath = "C:\\secrets\\key.pem";`:                   `C:\\secrets\\key.pem`,
+		`set KEYSTORE=D:\build\release.jks`:                          `D:\build\release.jks`,
+	} {
+		results := NewDetectionResults()
+		additions := []git_repo.Addition{git_repo.NewAddition("deploy.py", []byte(line))}
+
+		NewKeyPathDetector().Test(additions, keyPathEnabled, results)
+
+		if assert.Len(t, results.GetFailures("deploy.py"), 1, "Expected the key path in %s to be flagged", line) {
+			failure := results.GetFailures("deploy.py")[0]
+			assert.Equal(t, "Expected file to not to contain hardcoded paths to private keys such as: "+keyPath, failure.Message)
+			assert.Equal(t, LowSeverity, failure.Severity)
+		}
+	}
+}
+
+func TestShouldNotFlagRelativeOrUnrelatedPaths(t *testing.T) {
+	for _, line := range []string{
+		`key_filename = "keys/dev.pem"`,
+		`cert = open("./certs/server.key")`,
+		`url = "https://example.com/downloads/key.pem"`,
+		`log_file = "/var/log/app.log"`,
+	} {
+		results := NewDetectionResults()
+		additions := []git_repo.Addition{git_repo.NewAddition("deploy.py", []byte(line))}
+
+		NewKeyPathDetector().Test(additions, keyPathEnabled, results)
+
+		assert.False(t, results.HasFailures(), "Expected %s to not be flagged", line)
+	}
+}
+
+func TestShouldNotFlagKeyPathsUnlessEnabled(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("deploy.py", []byte(`key = "/home/deploy/.ssh/id_rsa"`))}
+
+	DefaultChain().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasFailures(), "Expected the key path detector to be off by default")
+	assert.NotContains(t, results.ExecutedDetectors(), KeyPathDetectorName)
+}
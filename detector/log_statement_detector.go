@@ -0,0 +1,91 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const minLogSecretLength = 6
+
+//logCallPattern matches the logging and printing calls of common languages, such as print(, console.log(, logger.info(, fmt.Printf( and echo
+var logCallPattern = regexp.MustCompile(`(?:\b(?:print|println|printf|puts|echo|NSLog|console\.(?:log|debug|info|warn|error|trace)|fmt\.(?:Print|Printf|Println|Sprintf|Fprintf|Fprintln)|(?:[A-Za-z_]*[Ll]og(?:ger|ging)?|System\.(?:out|err)|Console)\.[A-Za-z]+)\s*\(|\b(?:puts|echo|print|say)\s+["'])`)
+
+var stringLiteralPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|` + "`[^`]*`")
+
+//logSecretPattern matches a secret named in a string literal along with the value given to it, such as "password: hunter2"
+var logSecretPattern = regexp.MustCompile(`(?i)(?:passw(?:or)?d|pwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key|credential)s?\s*[:=]\s*([^\s"'` + "`" + `,;]+)`)
+
+//logPlaceholderPattern matches the format placeholders and interpolations that stand for a value given to the log call
+var logPlaceholderPattern = regexp.MustCompile(`%[-+#0-9.]*[a-zA-Z]|\{[^}]*\}|\$\{[^}]*\}|\$[A-Za-z_][A-Za-z0-9_]*|#\{[^}]*\}`)
+
+//LogStatementDetector flags literal secrets in the string literals passed to logging and printing calls.
+//Logging a variable holding a secret is left to code review, as the detector cannot know what the variable holds.
+type LogStatementDetector struct {
+	base64Detector *Base64Detector
+}
+
+//NewLogStatementDetector returns a LogStatementDetector that knows about the logging and printing calls of common languages
+func NewLogStatementDetector() *LogStatementDetector {
+	return &LogStatementDetector{NewBase64Detector()}
+}
+
+//Test tests the logging and printing calls in the Additions to ensure that they don't print literal secrets
+func (ld *LogStatementDetector) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	for _, addition := range additions {
+		if ignoreConfig.Deny(addition, "filecontent") || cc.IsScanNotRequired(addition) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Ignoring addition as it was specified to be ignored.")
+			result.Ignore(addition.Path, "filecontent")
+			continue
+		}
+		for _, literal := range ld.loggedSecrets(addition.Data) {
+			if !ignoreConfig.isReportable(LogStatementDetectorName, addition.Path, literal.Text, result) {
+				continue
+			}
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Failing file as it logs a literal secret.")
+			result.FailAt(addition.Path, "filecontent", fmt.Sprintf("Expected log and print statements to not to contain secrets such as: %s", literal.Text), addition.Commits, HighSeverity, literal)
+		}
+	}
+}
+
+//loggedSecrets returns the findings of the string literals of the logging calls that contain a secret, at the offsets they were logged at
+func (ld *LogStatementDetector) loggedSecrets(data []byte) []Finding {
+	var literals []Finding
+	lineStart := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		if call := logCallPattern.FindStringIndex(line); call != nil {
+			for _, match := range stringLiteralPattern.FindAllStringIndex(line[call[0]:], -1) {
+				literal := line[call[0]+match[0] : call[0]+match[1]]
+				if ld.containsSecret(literal[1 : len(literal)-1]) {
+					literals = append(literals, findingAt(data, lineStart+call[0]+match[0], literal))
+				}
+			}
+		}
+		lineStart += len(line) + 1
+	}
+	return literals
+}
+
+func (ld *LogStatementDetector) containsSecret(text string) bool {
+	text = logPlaceholderPattern.ReplaceAllString(text, " ")
+	for _, match := range logSecretPattern.FindAllStringSubmatch(text, -1) {
+		if len(match[1]) >= minLogSecretLength {
+			return true
+		}
+	}
+	for _, word := range strings.Fields(text) {
+		if ld.base64Detector.checkBase64Encoding(word) != "" {
+			return true
+		}
+	}
+	return false
+}
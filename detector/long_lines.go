@@ -0,0 +1,55 @@
+package detector
+
+import (
+	"fmt"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const (
+	//TruncateLongLines scans only the first max_line_length characters of lines that are longer. This is the default
+	TruncateLongLines string = "truncate"
+	//SkipLongLines does not scan lines that are longer than max_line_length, and warns about them instead
+	SkipLongLines string = "skip"
+)
+
+//LongLineMode returns the long_line_action of the config, which truncates the long lines unless configured otherwise, failing on an unknown action
+func (i TalismanRCIgnore) LongLineMode() (string, error) {
+	switch i.LongLineAction {
+	case "", TruncateLongLines:
+		return TruncateLongLines, nil
+	case SkipLongLines:
+		return SkipLongLines, nil
+	}
+	return "", fmt.Errorf("invalid long_line_action: unknown action %q, expected one of truncate or skip", i.LongLineAction)
+}
+
+//limitLineLength returns the contents of the addition with the lines longer than the configured max_line_length either truncated or skipped.
+//Skipped lines are reported as warnings, so that they can still be reviewed. Without a max_line_length or a valid action, the contents are returned as they are.
+func (i TalismanRCIgnore) limitLineLength(addition git_repo.Addition, action string, result *DetectionResults) []byte {
+	if i.MaxLineLength <= 0 || action == "" {
+		return addition.Data
+	}
+	lines := strings.Split(string(addition.Data), "\n")
+	var limited []string
+	for lineNumber, line := range lines {
+		if len(line) <= i.MaxLineLength {
+			limited = append(limited, line)
+			continue
+		}
+		if action == SkipLongLines {
+			log.WithFields(log.Fields{
+				"filePath":   addition.Path,
+				"lineNumber": lineNumber + 1,
+			}).Info("Skipping line as it is longer than the max line length.")
+			result.WarnAt(addition.Path, "filecontent", fmt.Sprintf("Line %d was not scanned as it is longer than the max line length (%d)", lineNumber+1, i.MaxLineLength), addition.Commits, LowSeverity, Finding{Position: Position{Line: lineNumber + 1, Column: 1}})
+			limited = append(limited, "")
+			continue
+		}
+		limited = append(limited, line[:i.MaxLineLength])
+	}
+	return []byte(strings.Join(limited, "\n"))
+}
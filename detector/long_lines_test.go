@@ -0,0 +1,88 @@
+package detector
+
+import (
+	"strings"
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const longLineSecret = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+const normalLineSecret = "68656C6C6F20776F726C6421"
+
+var minifiedLine = "var a=" + strings.Repeat("b", 5000) + "; var k=\"" + longLineSecret + "\";"
+
+func TestShouldTruncateLongLinesForScanning(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("bundle.js", []byte(minifiedLine+"\n"+normalLineSecret))}
+	ignores := NewTalismanRCIgnore([]byte("max_line_length: 1000\nlong_line_action: truncate"))
+
+	NewChain().AddNamedDetector(FileContentDetectorName, "filecontent", NewFileContentDetector()).Test(additions, ignores, results)
+
+	messages := strings.Join(getFailureMessages(results, additions[0].Path), " ")
+	assert.NotContains(t, messages, longLineSecret, "Expected content beyond the max line length to not be scanned")
+	assert.Contains(t, messages, normalLineSecret, "Expected normal lines to be scanned as usual")
+	assert.False(t, results.HasWarnings())
+}
+
+func TestShouldSkipLongLinesWithAWarning(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("bundle.js", []byte(normalLineSecret+"\n"+longLineSecret+minifiedLine))}
+	ignores := NewTalismanRCIgnore([]byte("max_line_length: 1000\nlong_line_action: skip"))
+
+	NewChain().AddNamedDetector(FileContentDetectorName, "filecontent", NewFileContentDetector()).Test(additions, ignores, results)
+
+	messages := strings.Join(getFailureMessages(results, additions[0].Path), " ")
+	assert.NotContains(t, messages, longLineSecret, "Expected the long line to be skipped")
+	assert.Contains(t, messages, normalLineSecret, "Expected normal lines to be scanned as usual")
+	assert.Equal(t, "Line 2 was not scanned as it is longer than the max line length (1000)", results.Results[0].WarningList[0].Message)
+}
+
+func TestShouldScanLongLinesWhenNoMaxLineLengthIsConfigured(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("bundle.js", []byte(minifiedLine))}
+
+	NewChain().AddNamedDetector(FileContentDetectorName, "filecontent", NewFileContentDetector()).Test(additions, TalismanRCIgnore{}, results)
+
+	assert.Contains(t, strings.Join(getFailureMessages(results, additions[0].Path), " "), longLineSecret)
+}
+
+func TestShouldLimitLongLinesForEveryContentDetector(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("bundle.js", []byte(minifiedLine+"password=hunter2\n"))}
+	ignores := NewTalismanRCIgnore([]byte("max_line_length: 1000"))
+
+	NewChain().AddNamedDetector(PatternDetectorName, "filecontent", NewPatternDetector()).Test(additions, ignores, results)
+
+	assert.False(t, results.HasFailures(), "Expected the pattern beyond the max line length to not be scanned")
+}
+
+func TestShouldWarnAboutASkippedLineOnceForAllContentDetectors(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("bundle.js", []byte(minifiedLine))}
+	ignores := NewTalismanRCIgnore([]byte("max_line_length: 1000\nlong_line_action: skip"))
+
+	DefaultChain().Test(additions, ignores, results)
+
+	assert.Len(t, results.Results[0].WarningList, 1)
+}
+
+func TestUnknownLongLineActionsShouldBeReportedAsConfigErrors(t *testing.T) {
+	_, err := NewTalismanRCIgnore([]byte("max_line_length: 1000\nlong_line_action: drop")).LongLineMode()
+
+	if assert.Error(t, err) {
+		assert.Equal(t, `invalid long_line_action: unknown action "drop", expected one of truncate or skip`, err.Error())
+	}
+}
+
+func TestUnknownLongLineActionsShouldNotLimitTheLines(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("bundle.js", []byte(minifiedLine))}
+	ignores := NewTalismanRCIgnore([]byte("max_line_length: 1000\nlong_line_action: drop"))
+
+	NewChain().AddNamedDetector(FileContentDetectorName, "filecontent", NewFileContentDetector()).Test(additions, ignores, results)
+
+	assert.Contains(t, strings.Join(getFailureMessages(results, additions[0].Path), " "), longLineSecret)
+}
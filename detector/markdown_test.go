@@ -0,0 +1,59 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var markdownFencesOnly = NewTalismanRCIgnore([]byte("markdown_fences_only: true"))
+
+func TestShouldDetectSecretsInsideMarkdownCodeFences(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("docs/setup.md", []byte("# Setup\n\n```yaml\ndb:\n  password: SuperSecret123!\n```\n"))}
+
+	NewChain().AddNamedDetector(PatternDetectorName, "filecontent", NewPatternDetector()).Test(additions, markdownFencesOnly, results)
+
+	assert.True(t, results.HasFailures(), "Expected the secret in the code fence to be detected")
+	assert.Equal(t, 5, results.GetFailures("docs/setup.md")[0].Line)
+}
+
+func TestShouldNotDetectSecretLookingStringsInMarkdownProse(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("docs/setup.md", []byte("Change the admin password: ThisIsNotASecret in the UI\n~~~\nmake setup\n~~~\n"))}
+
+	NewChain().AddNamedDetector(PatternDetectorName, "filecontent", NewPatternDetector()).Test(additions, markdownFencesOnly, results)
+
+	assert.False(t, results.HasFailures(), "Expected the prose to not be scanned")
+}
+
+func TestShouldScanMarkdownProseWithoutTheOption(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("docs/setup.md", []byte("Change the admin password: ThisIsNotASecret in the UI\n"))}
+
+	NewChain().AddNamedDetector(PatternDetectorName, "filecontent", NewPatternDetector()).Test(additions, TalismanRCIgnore{}, results)
+
+	assert.True(t, results.HasFailures(), "Expected the whole file to be scanned by default")
+}
+
+func TestShouldSuppressExampleKeysInMarkdownCodeFences(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("README.md", []byte("```\nsecret: wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY\n```\n"))}
+
+	NewChain().AddNamedDetector(FileContentDetectorName, "filecontent", NewFileContentDetector()).Test(additions, markdownFencesOnly, results)
+
+	assert.False(t, results.HasFailures(), "Expected the documented example key to be suppressed")
+}
+
+func TestShouldOnlyScanMarkdownCodeFencesWithEveryContentDetector(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("docs/logging.md", []byte("Never write print(\"password: hunter2\") in the code.\n```python\nprint(\"secret: opensesame\")\n```\n"))}
+
+	NewChain().AddNamedDetector(LogStatementDetectorName, "filecontent", NewLogStatementDetector()).Test(additions, markdownFencesOnly, results)
+
+	if assert.Len(t, results.GetFailures("docs/logging.md"), 1, "Expected only the code fence to be scanned") {
+		assert.Equal(t, 3, results.GetFailures("docs/logging.md")[0].Line)
+	}
+}
@@ -0,0 +1,61 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//metadataFile describes the metadata that an operating system or an editor leaves next to the files of a project
+type metadataFile struct {
+	kind    string
+	pattern *regexp.Regexp
+}
+
+//MetadataFileDetector flags the metadata files of operating systems and editors, such as .DS_Store or .idea/workspace.xml,
+//which are committed by accident and can reveal the internal directory structure of a project or the local paths of a machine.
+//It is an opt-in detector, which only runs when enabled under detectors.metadatafile in the .talismanrc.
+type MetadataFileDetector struct {
+	files []metadataFile
+}
+
+//NewMetadataFileDetector returns a MetadataFileDetector that knows about the metadata files of macOS, Windows, Vim and JetBrains IDEs
+func NewMetadataFileDetector() *MetadataFileDetector {
+	return &MetadataFileDetector{[]metadataFile{
+		{"macOS Finder metadata", regexp.MustCompile(`(^|/)\.DS_Store$`)},
+		{"Windows thumbnail cache", regexp.MustCompile(`(?i)(^|/)e?thumbs\.db$`)},
+		{"Windows folder settings", regexp.MustCompile(`(?i)(^|/)desktop\.ini$`)},
+		{"Vim swap file", regexp.MustCompile(`(^|/)[^/]*\.sw[op]$`)},
+		{"JetBrains IDE workspace", regexp.MustCompile(`(^|/)\.idea/workspace\.xml$`)},
+	}}
+}
+
+func (md *MetadataFileDetector) isEnabled(ignoreConfig TalismanRCIgnore) bool {
+	return ignoreConfig.Detectors[MetadataFileDetectorName].Enabled
+}
+
+//Test tests the paths of the Additions to ensure that they are not operating system or editor metadata
+func (md *MetadataFileDetector) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	for _, addition := range additions {
+		if ignoreConfig.Deny(addition, "filename") || cc.IsScanNotRequired(addition) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Ignoring addition as it was specified to be ignored.")
+			result.Ignore(addition.Path, "filename")
+			continue
+		}
+		for _, file := range md.files {
+			if file.pattern.MatchString(string(addition.Path)) {
+				log.WithFields(log.Fields{
+					"filePath": addition.Path,
+					"kind":     file.kind,
+				}).Info("Failing file as it is operating system or editor metadata.")
+				result.Fail(addition.Path, "filename", fmt.Sprintf("The file %q is %s, which can reveal internal paths and should not be committed", addition.Path, file.kind), addition.Commits, LowSeverity)
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var metadataFileEnabled = NewTalismanRCIgnore([]byte("detectors:\n  metadatafile:\n    enabled: true\n"))
+
+func TestShouldFlagMetadataFilesWhenEnabled(t *testing.T) {
+	for filePath, kind := range map[string]string{
+		".DS_Store":           "macOS Finder metadata",
+		"assets/.DS_Store":    "macOS Finder metadata",
+		"images/Thumbs.db":    "Windows thumbnail cache",
+		"docs/desktop.ini":    "Windows folder settings",
+		"src/.main.go.swp":    "Vim swap file",
+		".idea/workspace.xml": "JetBrains IDE workspace",
+	} {
+		results := NewDetectionResults()
+		additions := []git_repo.Addition{git_repo.NewAddition(filePath, []byte("/Users/jdoe/work/internal-project"))}
+
+		NewMetadataFileDetector().Test(additions, metadataFileEnabled, results)
+
+		if assert.Len(t, results.GetFailures(git_repo.FilePath(filePath)), 1, "Expected %s to be flagged", filePath) {
+			failure := results.GetFailures(git_repo.FilePath(filePath))[0]
+			assert.Equal(t, "The file \""+filePath+"\" is "+kind+", which can reveal internal paths and should not be committed", failure.Message)
+			assert.Equal(t, LowSeverity, failure.Severity)
+			assert.Equal(t, "filename", failure.Category)
+		}
+	}
+}
+
+func TestShouldNotFlagNormalFilesAsMetadata(t *testing.T) {
+	for _, filePath := range []string{"main.go", "docs/swp.md", "thumbs.dbx", ".idea/codeStyles/Project.xml", "workspace.xml"} {
+		results := NewDetectionResults()
+		additions := []git_repo.Addition{git_repo.NewAddition(filePath, []byte("content"))}
+
+		NewMetadataFileDetector().Test(additions, metadataFileEnabled, results)
+
+		assert.False(t, results.HasFailures(), "Expected %s to not be flagged", filePath)
+	}
+}
+
+func TestShouldNotFlagMetadataFilesUnlessEnabled(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("assets/.DS_Store", []byte("metadata"))}
+
+	NewChain().AddNamedDetector(MetadataFileDetectorName, "filename", NewMetadataFileDetector()).Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasFailures(), "Expected the metadata file detector to be off by default")
+}
@@ -0,0 +1,100 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+var netrcPathPattern = regexp.MustCompile(`(^|/)[._]?netrc$`)
+
+var curlConfigPathPattern = regexp.MustCompile(`(^|/)([._]?curlrc|[^/]+\.curlrc|curl\.conf|curl\.config)$`)
+
+var netrcTokenPattern = regexp.MustCompile(`\S+`)
+
+//curlUserPattern matches the options of a curl config that pass credentials, written either as command line options or as config file options
+var curlUserPattern = regexp.MustCompile(`^\s*(?:--?)?(user|u|proxy-user|U)(?:\s*[=:]\s*|\s+)(?:"([^"]*)"|'([^']*)'|(\S+))`)
+
+//NetrcDetector flags the passwords stored in plaintext in .netrc files and curl config files, as read by curl -K
+type NetrcDetector struct{}
+
+//NewNetrcDetector returns a NetrcDetector
+func NewNetrcDetector() *NetrcDetector {
+	return &NetrcDetector{}
+}
+
+//Test tests the .netrc and curl config Additions to ensure that they don't contain passwords
+func (nd *NetrcDetector) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	for _, addition := range additions {
+		var findings []string
+		if netrcPathPattern.MatchString(string(addition.Path)) {
+			findings = netrcPasswords(string(addition.Data))
+		} else if curlConfigPathPattern.MatchString(string(addition.Path)) {
+			findings = curlConfigPasswords(string(addition.Data))
+		} else {
+			continue
+		}
+		if ignoreConfig.Deny(addition, "filecontent") || cc.IsScanNotRequired(addition) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Ignoring addition as it was specified to be ignored.")
+			result.Ignore(addition.Path, "filecontent")
+			continue
+		}
+		for _, finding := range ignoreConfig.reportableFindings(NetrcDetectorName, addition.Path, findings, result) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Failing file as it contains a plaintext password for curl or netrc.")
+			result.FailAt(addition.Path, "filecontent", fmt.Sprintf("Expected file to not to contain plaintext passwords such as: %s", finding), addition.Commits, HighSeverity, findingIn(addition.Data, finding))
+		}
+	}
+}
+
+//netrcPasswords returns the password tokens of the .netrc contents along with their values, as written in the file.
+//The tokens may be spread over any number of lines, and the macros defined by macdef are not parsed, as they run until the next blank line.
+func netrcPasswords(content string) []string {
+	var passwords []string
+	tokens := netrcTokenPattern.FindAllStringIndex(content, -1)
+	macroEnd := -1
+	for i := 0; i < len(tokens); i++ {
+		start, end := tokens[i][0], tokens[i][1]
+		if start < macroEnd {
+			continue
+		}
+		switch content[start:end] {
+		case "macdef":
+			macroEnd = len(content)
+			if blankLine := strings.Index(content[end:], "\n\n"); blankLine != -1 {
+				macroEnd = end + blankLine
+			}
+		case "password":
+			if i+1 < len(tokens) && isPopulatedCredential(content[tokens[i+1][0]:tokens[i+1][1]]) {
+				passwords = append(passwords, content[start:tokens[i+1][1]])
+				i++
+			}
+		}
+	}
+	return passwords
+}
+
+//curlConfigPasswords returns the lines of the curl config that pass a user along with a password
+func curlConfigPasswords(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		match := curlUserPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		credentials := match[2] + match[3] + match[4]
+		separator := strings.Index(credentials, ":")
+		if separator != -1 && isPopulatedCredential(credentials[separator+1:]) {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,76 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testNetrcDetector(path string, content string) *DetectionResults {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition(path, []byte(content))}
+	NewNetrcDetector().Test(additions, TalismanRCIgnore{}, results)
+	return results
+}
+
+func TestShouldFlagThePasswordOfANetrc(t *testing.T) {
+	results := testNetrcDetector(".netrc", "machine api.example.com\n  login deploy\n  password s3cr3tPa55\n")
+
+	assert.True(t, results.HasFailures(), "Expected the password of the .netrc to be flagged")
+	failures := results.GetFailures(".netrc")
+	assert.Len(t, failures, 1)
+	assert.Equal(t, "Expected file to not to contain plaintext passwords such as: password s3cr3tPa55", failures[0].Message)
+	assert.Equal(t, 3, failures[0].Line)
+}
+
+func TestShouldFlagThePasswordsOfNetrcEntriesWrittenOnASingleLine(t *testing.T) {
+	results := testNetrcDetector("home/_netrc", "machine a.example.com login one password first1\nmachine b.example.com login two password second2\ndefault login anonymous password guest123")
+
+	assert.Len(t, results.GetFailures("home/_netrc"), 3)
+}
+
+func TestShouldNotFlagANetrcWithOnlyALogin(t *testing.T) {
+	results := testNetrcDetector(".netrc", "machine api.example.com\n  login deploy\n")
+
+	assert.False(t, results.HasFailures(), "Expected a .netrc without a password to not be flagged")
+}
+
+func TestShouldNotFlagTheMacrosOfANetrc(t *testing.T) {
+	results := testNetrcDetector(".netrc", "machine ftp.example.com login deploy\nmacdef init\necho password hunter22\n\nmachine other.example.com login guest password $FTP_PASSWORD\n")
+
+	assert.False(t, results.HasFailures(), "Expected the macro and the environment reference to not be flagged")
+}
+
+func TestShouldFlagTheUserAndPasswordOfACurlConfig(t *testing.T) {
+	for _, line := range []string{
+		"--user deploy:s3cr3tPa55",
+		"-u deploy:s3cr3tPa55",
+		`user = "deploy:s3cr3tPa55"`,
+		"proxy-user: proxy:s3cr3tPa55",
+	} {
+		results := testNetrcDetector("api.curlrc", "url = \"https://api.example.com\"\n"+line+"\n")
+
+		assert.True(t, results.HasFailures(), "Expected %s to be flagged", line)
+	}
+}
+
+func TestShouldNotFlagACurlConfigWithoutAPassword(t *testing.T) {
+	for _, line := range []string{
+		"--user deploy",
+		"user = \"deploy:\"",
+		"user = \"deploy:${API_PASSWORD}\"",
+		"user-agent = \"talisman:1.0\"",
+	} {
+		results := testNetrcDetector(".curlrc", line+"\n")
+
+		assert.False(t, results.HasFailures(), "Expected %s to not be flagged", line)
+	}
+}
+
+func TestShouldOnlyParseNetrcAndCurlConfigFiles(t *testing.T) {
+	results := testNetrcDetector("docs/setup.md", "machine api.example.com login deploy password s3cr3tPa55\n--user deploy:s3cr3tPa55\n")
+
+	assert.False(t, results.HasFailures())
+}
@@ -0,0 +1,100 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const minOAuthSecretLength = 8
+
+//oauthSecretFields are the normalized names of the JSON fields holding OAuth secrets, as written to credentials.json and token.json
+var oauthSecretFields = map[string]string{
+	"refreshtoken": "refresh tokens",
+	"clientsecret": "client secrets",
+}
+
+//oauthPlaceholderPattern matches the values that stand in for a secret in sample configs, such as YOUR_CLIENT_SECRET or <refresh token>
+var oauthPlaceholderPattern = regexp.MustCompile(`(?i)^(your[_ -].*|.*[_ -]here|<.*>|\{\{.*\}\}|\$\{.*\}|\*+|x+|changeme|redacted|placeholder|example|todo|none|null)$`)
+
+//OAuthTokenDetector flags the OAuth refresh tokens and client secrets of JSON configs, such as the credentials.json and token.json of Google APIs.
+//The fields are recognized at any depth, so that the secrets of the installed and web sections of credentials.json are found as well.
+type OAuthTokenDetector struct{}
+
+//NewOAuthTokenDetector returns an OAuthTokenDetector
+func NewOAuthTokenDetector() *OAuthTokenDetector {
+	return &OAuthTokenDetector{}
+}
+
+//Test tests the contents of the Additions to ensure that they don't hold OAuth refresh tokens or client secrets
+func (od *OAuthTokenDetector) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	for _, addition := range additions {
+		if ignoreConfig.Deny(addition, "filecontent") || cc.IsScanNotRequired(addition) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Ignoring addition as it was specified to be ignored.")
+			result.Ignore(addition.Path, "filecontent")
+			continue
+		}
+		var document interface{}
+		if json.Unmarshal(addition.Data, &document) != nil {
+			continue
+		}
+		for _, secret := range oauthSecrets(document) {
+			if len(ignoreConfig.reportableFindings(OAuthTokenDetectorName, addition.Path, []string{secret.value}, result)) == 0 {
+				continue
+			}
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+				"field":    secret.field,
+			}).Info("Failing file as it contains an OAuth secret.")
+			result.FailAt(addition.Path, "filecontent", fmt.Sprintf("Expected file to not to contain OAuth %s such as: %s", oauthSecretFields[normalizedFieldName(secret.field)], secret.value), addition.Commits, HighSeverity, findingAssignedTo(addition.Data, secret.value, secret.field))
+		}
+	}
+}
+
+type oauthSecret struct {
+	field string
+	value string
+}
+
+//oauthSecrets walks the document, returning the non-empty values of the OAuth secret fields in the order they are found
+func oauthSecrets(document interface{}) []oauthSecret {
+	var secrets []oauthSecret
+	switch node := document.(type) {
+	case map[string]interface{}:
+		var fields []string
+		for field := range node {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			value, isString := node[field].(string)
+			if _, isSecretField := oauthSecretFields[normalizedFieldName(field)]; isSecretField && isString {
+				value = strings.TrimSpace(value)
+				if len(value) >= minOAuthSecretLength && !oauthPlaceholderPattern.MatchString(value) {
+					secrets = append(secrets, oauthSecret{field, value})
+				}
+				continue
+			}
+			secrets = append(secrets, oauthSecrets(node[field])...)
+		}
+	case []interface{}:
+		for _, element := range node {
+			secrets = append(secrets, oauthSecrets(element)...)
+		}
+	}
+	return secrets
+}
+
+//normalizedFieldName returns the name of the field in lower case without separators, so that refresh_token and refreshToken are the same
+func normalizedFieldName(field string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(field))
+}
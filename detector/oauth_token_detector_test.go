@@ -0,0 +1,96 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const googleTokenJSON = `{
+  "token": "ya29.a0AfH6SMBx",
+  "refresh_token": "1//0gLx9Kf3QwErTyUiOpAsDfGhJkLzXcVbNm",
+  "token_uri": "https://oauth2.googleapis.com/token",
+  "client_id": "1234567890-abc.apps.googleusercontent.com",
+  "scopes": ["https://www.googleapis.com/auth/drive"]
+}`
+
+func TestShouldFlagTheRefreshTokenOfATokenJSON(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("token.json", []byte(googleTokenJSON))}
+
+	NewOAuthTokenDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.True(t, results.HasFailures(), "Expected the refresh token to be flagged")
+	failures := results.GetFailures("token.json")
+	assert.Len(t, failures, 1)
+	assert.Equal(t, HighSeverity, failures[0].Severity)
+	assert.Equal(t, "Expected file to not to contain OAuth refresh tokens such as: 1//0gLx9Kf3QwErTyUiOpAsDfGhJkLzXcVbNm", failures[0].Message)
+	assert.Equal(t, 3, failures[0].Line)
+}
+
+func TestShouldFlagTheClientSecretsNestedInACredentialsJSON(t *testing.T) {
+	credentials := `{"installed": {"client_id": "1234567890-abc.apps.googleusercontent.com", "client_secret": "GOCSPX-1a2B3c4D5e6F7g8H9i0JkLmNoPq"},
+"web": [{"clientSecret": "Zx8Cv7Bn6Mq5Wr4Et3Yu2Io1"}]}`
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("credentials.json", []byte(credentials))}
+
+	NewOAuthTokenDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	failures := results.GetFailures("credentials.json")
+	assert.Len(t, failures, 2, "Expected the client secrets of both sections to be flagged")
+}
+
+func TestShouldNotFlagPlaceholderOAuthSecrets(t *testing.T) {
+	config := `{"installed": {"client_secret": "YOUR_CLIENT_SECRET", "refresh_token": "<refresh token>"},
+"web": {"client_secret": "${CLIENT_SECRET}", "refresh_token": ""}, "other": {"refresh_token": "paste-token-here"}}`
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("credentials.json", []byte(config))}
+
+	NewOAuthTokenDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasFailures(), "Expected placeholder values to not be flagged")
+}
+
+func TestShouldNotFlagUnrelatedJSON(t *testing.T) {
+	for _, content := range []string{
+		`{"name": "talisman", "version": "1.0.0", "dependencies": {"secret-sauce": "^2.1.0"}}`,
+		`[{"token_uri": "https://oauth2.googleapis.com/token"}, {"refresh_interval": "30s"}]`,
+		`refresh_token: 1//0gLx9Kf3QwErTyUiOpAsDfGhJkLzXcVbNm`,
+	} {
+		results := NewDetectionResults()
+		additions := []git_repo.Addition{git_repo.NewAddition("config.json", []byte(content))}
+
+		NewOAuthTokenDetector().Test(additions, TalismanRCIgnore{}, results)
+
+		assert.False(t, results.HasFailures(), "Expected %s to not be flagged", content)
+	}
+}
+
+func TestShouldNotFlagOAuthSecretsOfIgnoredFiles(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("token.json", []byte(googleTokenJSON))}
+	ignores := NewTalismanRCIgnore([]byte("fileignoreconfig:\n- filename: token.json\n  ignore_detectors: [filecontent]\n"))
+
+	NewOAuthTokenDetector().Test(additions, ignores, results)
+
+	assert.False(t, results.HasFailures())
+	assert.True(t, results.HasIgnores())
+}
+
+func TestShouldReportTheOAuthSecretAtTheFieldItIsAssignedTo(t *testing.T) {
+	token := `{
+  "description": "refreshes with 1//0gLx9Kf3QwErTyUiOpAsDfGhJkLzXcVbNm",
+  "refresh_token": "1//0gLx9Kf3QwErTyUiOpAsDfGhJkLzXcVbNm"
+}`
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("token.json", []byte(token))}
+
+	NewOAuthTokenDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	if assert.Len(t, results.GetFailures("token.json"), 1) {
+		assert.Equal(t, 3, results.GetFailures("token.json")[0].Line)
+		assert.Equal(t, 21, results.GetFailures("token.json")[0].Column)
+	}
+}
@@ -22,7 +22,7 @@ func (detector PatternDetector) Test(additions []git_repo.Addition, ignoreConfig
 			result.Ignore(addition.Path, "filecontent")
 			continue
 		}
-		detections := detector.secretsPattern.check(string(addition.Data))
+		detections := ignoreConfig.reportableFindings(PatternDetectorName, addition.Path, detector.secretsPattern.check(string(addition.Data)), result)
 		for _, detection := range detections {
 			if detection != "" {
 				if string(addition.Name) == DefaultRCFileName {
@@ -30,13 +30,13 @@ func (detector PatternDetector) Test(additions []git_repo.Addition, ignoreConfig
 						"filePath": addition.Path,
 						"pattern":  detection,
 					}).Warn("Warning file as it matched pattern.")
-					result.Warn(addition.Path, "filecontent", fmt.Sprintf("Potential secret pattern : %s", detection), addition.Commits)
+					result.WarnAt(addition.Path, "filecontent", fmt.Sprintf("Potential secret pattern : %s", detection), addition.Commits, HighSeverity, findingIn(addition.Data, detection))
 				} else {
 					log.WithFields(log.Fields{
 						"filePath": addition.Path,
 						"pattern":  detection,
 					}).Info("Failing file as it matched pattern.")
-					result.Fail(addition.Path, "filecontent", fmt.Sprintf("Potential secret pattern : %s", detection), addition.Commits)
+					result.FailAt(addition.Path, "filecontent", fmt.Sprintf("Potential secret pattern : %s", detection), addition.Commits, HighSeverity, findingIn(addition.Data, detection))
 				}
 			}
 		}
@@ -26,7 +26,7 @@ func TestShouldIgnorePasswordPatterns(t *testing.T) {
 	content := []byte("\"password\" : UnsafePassword")
 	filename := "secret.txt"
 	additions := []git_repo.Addition{git_repo.NewAddition(filename, content)}
-	fileIgnoreConfig := FileIgnoreConfig{filename, "833b6c24c8c2c5c7e1663226dc401b29c005492dc76a1150fc0e0f07f29d4cc3", []string{"filecontent"}}
+	fileIgnoreConfig := FileIgnoreConfig{FileName: filename, Checksum: "833b6c24c8c2c5c7e1663226dc401b29c005492dc76a1150fc0e0f07f29d4cc3", IgnoreDetectors: []string{"filecontent"}}
 	ignores := TalismanRCIgnore{FileIgnoreConfig:[]FileIgnoreConfig{fileIgnoreConfig}}
 
 	NewPatternDetector().Test(additions, ignores, results)
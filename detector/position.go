@@ -0,0 +1,56 @@
+package detector
+
+import (
+	"regexp"
+	"strings"
+)
+
+//Position represents the line and column at which a detection was found in a file, both starting from 1.
+//The zero Position is used for detections that apply to the whole file, such as its name or size.
+type Position struct {
+	Line   int
+	Column int
+}
+
+//Finding represents the text matched by a detector, along with its position in the file
+//The EndLine of a finding spanning several lines is the last line it spans, and is 0 for findings on a single line
+type Finding struct {
+	Text string
+	Position
+	EndLine int
+}
+
+//findingIn returns the Finding of the first occurrence of the text in the data.
+//It suits the texts that are unique to where they were found, such as whole lines. Detectors matching a value at a known offset use findingAt.
+func findingIn(data []byte, text string) Finding {
+	return Finding{Text: text, Position: positionOf(data, text)}
+}
+
+//findingAt returns the Finding of the text that was matched at the given byte offset in the data
+func findingAt(data []byte, offset int, text string) Finding {
+	preceding := string(data[:offset])
+	lineStart := strings.LastIndex(preceding, "\n") + 1
+	return Finding{Text: text, Position: Position{Line: strings.Count(preceding, "\n") + 1, Column: len([]rune(preceding[lineStart:])) + 1}}
+}
+
+//findingAssignedTo returns the Finding of the first occurrence of the value that is assigned to one of the keys, as in "key": "value",
+//key: value, key=value or key: [Bearer value], falling back to the first occurrence of the value if it is assigned to none of them.
+//It locates the values read from parsed documents, whose offsets in the data are not known.
+func findingAssignedTo(data []byte, value string, keys ...string) Finding {
+	for _, key := range keys {
+		pattern := regexp.MustCompile(`(?:^|[^\w.-])["']?` + regexp.QuoteMeta(key) + `["']?\s*[:=]\s*(?:[\[-]\s*)*["']?(?:[A-Za-z]+\s+)?(` + regexp.QuoteMeta(value) + `)`)
+		if match := pattern.FindSubmatchIndex(data); match != nil {
+			return findingAt(data, match[2], value)
+		}
+	}
+	return findingIn(data, value)
+}
+
+//positionOf returns the position of the first occurrence of the text in the data, or the zero Position if it does not occur
+func positionOf(data []byte, text string) Position {
+	index := strings.Index(string(data), text)
+	if text == "" || index == -1 {
+		return Position{}
+	}
+	return findingAt(data, index, text).Position
+}
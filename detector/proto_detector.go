@@ -0,0 +1,179 @@
+package detector
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//protoFormat describes how a kind of protobuf file marks its comments
+type protoFormat struct {
+	lineComment  string
+	blockComment bool
+}
+
+var (
+	protoSourceFormat = protoFormat{lineComment: "//", blockComment: true}
+	protoTextFormat   = protoFormat{lineComment: "#"}
+	protoJSONFormat   = protoFormat{}
+)
+
+//protoFormats are the formats of the protobuf definitions and of the text format messages, by extension
+var protoFormats = map[string]protoFormat{
+	".proto":     protoSourceFormat,
+	".textproto": protoTextFormat,
+	".txtpb":     protoTextFormat,
+	".pbtxt":     protoTextFormat,
+	".prototxt":  protoTextFormat,
+}
+
+//grpcServiceConfigPattern matches the JSON service configs of gRPC clients, such as library_grpc_service_config.json
+var grpcServiceConfigPattern = regexp.MustCompile(`(?i)(^|[_.-])service_config\.json$`)
+
+//protoDeclarationPattern matches the statements of a .proto file whose strings are paths or names rather than option values
+var protoDeclarationPattern = regexp.MustCompile(`^\s*(syntax|edition|import|package)\b`)
+
+//protoLiteralPattern matches the string literals along with the option, field or key they are assigned to, if any
+var protoLiteralPattern = regexp.MustCompile(`(?:["']?\(?([A-Za-z0-9_.-]+)\)?["']?\s*[:=]\s*)?(?:"((?:[^"\\]|\\.)*)"|'((?:[^'\\]|\\.)*)')`)
+
+//protoFieldPattern matches a field definition, so that the default value of a field is checked against the name of the field
+var protoFieldPattern = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\s*=\s*\d+\s*\[`)
+
+//ProtoDetector flags the API keys and secrets that .proto files embed in the values of their options, along with those of
+//the text format messages and gRPC service configs that go with them. Field names and types are never flagged,
+//and comments are only flagged when they hold a high entropy value.
+type ProtoDetector struct {
+	base64Detector *Base64Detector
+}
+
+//NewProtoDetector returns a ProtoDetector
+func NewProtoDetector() *ProtoDetector {
+	return &ProtoDetector{NewBase64Detector()}
+}
+
+//Test tests the protobuf files among the Additions to ensure that their option values and messages don't hold secrets
+func (pd *ProtoDetector) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	for _, addition := range additions {
+		format, isProto := protoFormatOf(addition)
+		if !isProto {
+			continue
+		}
+		if ignoreConfig.Deny(addition, "filecontent") || cc.IsScanNotRequired(addition) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Ignoring addition as it was specified to be ignored.")
+			result.Ignore(addition.Path, "filecontent")
+			continue
+		}
+		for _, line := range ignoreConfig.reportableFindings(ProtoDetectorName, addition.Path, pd.protoSecrets(string(addition.Data), format), result) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Failing file as it embeds a secret in a protobuf option or message.")
+			result.FailAt(addition.Path, "filecontent", fmt.Sprintf("Expected protobuf file to not to contain secrets in its options or messages such as: %s", line), addition.Commits, HighSeverity, findingIn(addition.Data, line))
+		}
+	}
+}
+
+func protoFormatOf(addition git_repo.Addition) (protoFormat, bool) {
+	if grpcServiceConfigPattern.MatchString(path.Base(string(addition.Path))) {
+		return protoJSONFormat, true
+	}
+	format, ok := protoFormats[strings.ToLower(path.Ext(string(addition.Path)))]
+	return format, ok
+}
+
+//protoSecrets returns the lines that hold a secret, either in the strings of their code or in their comments
+func (pd *ProtoDetector) protoSecrets(content string, format protoFormat) []string {
+	var secrets []string
+	inBlockComment := false
+	for _, line := range strings.Split(content, "\n") {
+		var code, comment string
+		code, comment, inBlockComment = splitProtoComment(line, format, inBlockComment)
+		if pd.hasSecretValue(code, format) || pd.hasHighEntropyWord(comment) {
+			secrets = append(secrets, strings.TrimSpace(line))
+		}
+	}
+	return secrets
+}
+
+//splitProtoComment separates the code of the line from its comments, given whether the line starts within a block comment.
+//It answers whether the next line starts within a block comment.
+func splitProtoComment(line string, format protoFormat, inBlockComment bool) (string, string, bool) {
+	var code, comment strings.Builder
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inBlockComment:
+			if strings.HasPrefix(line[i:], "*/") {
+				inBlockComment = false
+				i++
+			} else {
+				comment.WriteByte(c)
+			}
+		case quote != 0:
+			code.WriteByte(c)
+			if c == '\\' && i+1 < len(line) {
+				i++
+				code.WriteByte(line[i])
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			code.WriteByte(c)
+		case format.blockComment && strings.HasPrefix(line[i:], "/*"):
+			inBlockComment = true
+			i++
+		case format.lineComment != "" && strings.HasPrefix(line[i:], format.lineComment):
+			comment.WriteString(line[i+len(format.lineComment):])
+			return code.String(), comment.String(), inBlockComment
+		default:
+			code.WriteByte(c)
+		}
+	}
+	return code.String(), comment.String(), inBlockComment
+}
+
+//hasSecretValue answers true if the code assigns a literal to a secret-named option, field or key, or holds a high entropy literal
+func (pd *ProtoDetector) hasSecretValue(code string, format protoFormat) bool {
+	if format == protoSourceFormat && protoDeclarationPattern.MatchString(code) {
+		return false
+	}
+	for _, match := range protoLiteralPattern.FindAllStringSubmatch(code, -1) {
+		name, value := match[1], match[2]+match[3]
+		if name == "default" {
+			if field := protoFieldPattern.FindStringSubmatch(code); field != nil {
+				name = field[1]
+			}
+		}
+		if oauthPlaceholderPattern.MatchString(value) || !isPopulatedCredential(value) {
+			continue
+		}
+		if ciSecretNamePattern.MatchString(name) && len(value) >= minCISecretLength {
+			return true
+		}
+		if pd.base64Detector.checkBase64Encoding(value) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (pd *ProtoDetector) hasHighEntropyWord(comment string) bool {
+	words := strings.FieldsFunc(comment, func(r rune) bool {
+		return strings.ContainsRune(" \t\"'`,;:()[]{}<>", r)
+	})
+	for _, word := range words {
+		if pd.base64Detector.checkBase64Encoding(word) != "" {
+			return true
+		}
+	}
+	return false
+}
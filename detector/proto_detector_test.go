@@ -0,0 +1,112 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const protoServiceHeader = "syntax = \"proto3\";\npackage library.v1;\nimport \"google/api/annotations.proto\";\n"
+
+func TestShouldFlagSecretsInProtoOptions(t *testing.T) {
+	for _, line := range []string{
+		`option (library.v1.api_key) = "Tr0ub4dor-3xkcd";`,
+		`option (google.api.default_host) = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY";`,
+		`string password = 2 [default = "Tr0ub4dor-3xkcd"];`,
+		`rpc GetBook(GetBookRequest) returns (Book) { option (auth.rule) = { token: "c2VjcmV0LWtleS10aGF0LWxvb2tzLXJlYWwtMTIz" }; }`,
+	} {
+		results := NewDetectionResults()
+		additions := []git_repo.Addition{git_repo.NewAddition("proto/library.proto", []byte(protoServiceHeader+line+"\n"))}
+
+		NewProtoDetector().Test(additions, TalismanRCIgnore{}, results)
+
+		if assert.Len(t, results.GetFailures("proto/library.proto"), 1, "Expected %s to be flagged", line) {
+			failure := results.GetFailures("proto/library.proto")[0]
+			assert.Equal(t, "Expected protobuf file to not to contain secrets in its options or messages such as: "+line, failure.Message)
+			assert.Equal(t, HighSeverity, failure.Severity)
+			assert.Equal(t, 4, failure.Line)
+		}
+	}
+}
+
+func TestShouldNotFlagProtoFieldDefinitions(t *testing.T) {
+	content := protoServiceHeader + `option java_package = "com.example.library.secrets";
+option go_package = "github.com/example/library/v1;libraryv1";
+
+message Credentials {
+  string api_key = 1 [json_name = "apiKey"];
+  string password = 2;
+  optional string access_token = 3 [deprecated = true];
+  map<string, string> secret_labels = 4;
+  bytes private_key = 5 [(validate.rules).bytes.min_len = 32];
+}
+
+service Library {
+  rpc GetBook(GetBookRequest) returns (Book) {
+    option (google.api.http) = { get: "/v1/{name=shelves/*/books/*}" };
+  }
+}
+`
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("proto/library.proto", []byte(content))}
+
+	NewProtoDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasFailures(), "Expected the field definitions and benign options to not be flagged")
+}
+
+func TestShouldOnlyFlagProtoCommentsHoldingRealSecrets(t *testing.T) {
+	content := protoServiceHeader + `// The api_key option holds the key of the caller, such as "YOUR_API_KEY".
+/* Callers pass their password in the metadata,
+   never in the message. */
+message Request {}
+/*
+ * Fallback key: wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY
+ */
+`
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("proto/library.proto", []byte(content))}
+
+	NewProtoDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	if assert.Len(t, results.GetFailures("proto/library.proto"), 1) {
+		assert.Equal(t, 9, results.GetFailures("proto/library.proto")[0].Line)
+	}
+}
+
+func TestShouldFlagSecretsInTextFormatMessagesAndGRPCServiceConfigs(t *testing.T) {
+	for fileName, content := range map[string]string{
+		"testdata/client.textproto":               "# client of the staging service\nendpoint: \"staging.example.com\"\nauth_token: \"Tr0ub4dor-3xkcd\"\n",
+		"config/library_grpc_service_config.json": "{\n  \"methodConfig\": [{\"name\": [{\"service\": \"library.v1.Library\"}], \"timeout\": \"60s\"}],\n  \"apiKey\": \"Tr0ub4dor-3xkcd\"\n}\n",
+	} {
+		results := NewDetectionResults()
+		additions := []git_repo.Addition{git_repo.NewAddition(fileName, []byte(content))}
+
+		NewProtoDetector().Test(additions, TalismanRCIgnore{}, results)
+
+		if assert.Len(t, results.GetFailures(git_repo.FilePath(fileName)), 1, "Expected the secret of %s to be flagged", fileName) {
+			assert.Equal(t, 3, results.GetFailures(git_repo.FilePath(fileName))[0].Line)
+		}
+	}
+}
+
+func TestShouldNotScanFilesThatAreNotProtobuf(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("config/settings.json", []byte("{\"apiKey\": \"Tr0ub4dor-3xkcd\"}\n"))}
+
+	NewProtoDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasFailures())
+}
+
+func TestShouldNotFlagAllowedProtoValues(t *testing.T) {
+	results := NewDetectionResults()
+	ignores := NewTalismanRCIgnore([]byte("detectors:\n  proto:\n    allowed_patterns:\n    - 'Tr0ub4dor'\n"))
+	additions := []git_repo.Addition{git_repo.NewAddition("proto/library.proto", []byte(protoServiceHeader+`option (library.v1.api_key) = "Tr0ub4dor-3xkcd";`+"\n"))}
+
+	NewProtoDetector().Test(additions, ignores, results)
+
+	assert.False(t, results.HasFailures())
+}
@@ -0,0 +1,43 @@
+package detector
+
+import (
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	fileIgnoreConfigKey = "fileignoreconfig"
+	archiveKey          = "archive"
+)
+
+//PruneExpiredIgnores moves the file ignores of the .talismanrc contents that have expired at the given time into its archive section.
+//The rest of the contents, including comments, are kept as they are. The pruned ignores are returned along with the new contents.
+func PruneExpiredIgnores(contents []byte, now time.Time) ([]byte, []FileIgnoreConfig, error) {
+	editor := newRCFileEditor(contents)
+	block := editor.listBlock(fileIgnoreConfigKey)
+	if block == nil {
+		return contents, nil, nil
+	}
+	var expiredEntries []rcEntryRange
+	var pruned []FileIgnoreConfig
+	for _, entry := range block.entries {
+		fileIgnoreConfig, err := editor.fileIgnoreConfigOf(block, entry)
+		if err != nil {
+			return contents, nil, err
+		}
+		if fileIgnoreConfig.IsExpired(now) {
+			expiredEntries = append(expiredEntries, entry)
+			pruned = append(pruned, fileIgnoreConfig)
+		}
+	}
+	if len(pruned) == 0 {
+		return contents, nil, nil
+	}
+	editor.appendToList(archiveKey, editor.removeEntries(block, expiredEntries), block.itemIndent)
+	result := editor.contents()
+	if err := yaml.Unmarshal(result, &TalismanRCIgnore{}); err != nil {
+		return contents, nil, err
+	}
+	return result, pruned, nil
+}
@@ -0,0 +1,108 @@
+package detector
+
+import (
+	"testing"
+	"time"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var pruneTime = time.Date(2020, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+const rcFileWithExpiredIgnore = `# ignores reviewed by the security team
+fileignoreconfig:
+# fixture used by the integration tests
+- filename: fixtures/expired.pem
+  checksum: abc
+  ignore_detectors: []
+  expires: 2020-03-01
+- filename: fixtures/live.pem
+  checksum: def
+  ignore_detectors: []
+  expires: 2020-04-01
+scopeconfig:
+- scope: go
+`
+
+func TestShouldArchiveExpiredIgnores(t *testing.T) {
+	pruned, expired, err := PruneExpiredIgnores([]byte(rcFileWithExpiredIgnore), pruneTime)
+
+	assert.NoError(t, err)
+	assert.Len(t, expired, 1)
+	assert.Equal(t, "fixtures/expired.pem", expired[0].FileName)
+	assert.Equal(t, `# ignores reviewed by the security team
+fileignoreconfig:
+- filename: fixtures/live.pem
+  checksum: def
+  ignore_detectors: []
+  expires: 2020-04-01
+scopeconfig:
+- scope: go
+archive:
+# fixture used by the integration tests
+- filename: fixtures/expired.pem
+  checksum: abc
+  ignore_detectors: []
+  expires: 2020-03-01
+`, string(pruned))
+
+	rc := NewTalismanRCIgnore(pruned)
+	assert.Len(t, rc.FileIgnoreConfig, 1)
+	assert.Equal(t, "fixtures/live.pem", rc.FileIgnoreConfig[0].FileName)
+	assert.Len(t, rc.Archive, 1)
+	assert.Equal(t, "fixtures/expired.pem", rc.Archive[0].FileName)
+}
+
+func TestShouldAppendToAnExistingArchive(t *testing.T) {
+	contents := `fileignoreconfig:
+- filename: expired.pem
+  checksum: abc
+  expires: 2020-03-01
+archive: []
+`
+	pruned, expired, err := PruneExpiredIgnores([]byte(contents), pruneTime)
+
+	assert.NoError(t, err)
+	assert.Len(t, expired, 1)
+	assert.Equal(t, `fileignoreconfig:
+archive:
+- filename: expired.pem
+  checksum: abc
+  expires: 2020-03-01
+`, string(pruned))
+}
+
+func TestShouldLeaveTheFileUntouchedWhenNoIgnoreHasExpired(t *testing.T) {
+	contents := []byte("fileignoreconfig:\n- filename: live.pem\n  checksum: def\n  expires: 2020-03-10\n")
+
+	pruned, expired, err := PruneExpiredIgnores(contents, pruneTime)
+
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+	assert.Equal(t, contents, pruned)
+}
+
+func TestIgnoresShouldExpireAtTheEndOfTheirExpiryDate(t *testing.T) {
+	assert.False(t, FileIgnoreConfig{Expires: "2020-03-10"}.IsExpired(pruneTime))
+	assert.True(t, FileIgnoreConfig{Expires: "2020-03-09"}.IsExpired(pruneTime))
+	assert.False(t, FileIgnoreConfig{}.IsExpired(pruneTime), "Expected ignores without an expiry date to never expire")
+	assert.False(t, FileIgnoreConfig{Expires: "next week"}.IsExpired(pruneTime), "Expected unparsable expiry dates to never expire")
+}
+
+func TestUnparsableExpiryDatesShouldBeReportedAsConfigErrors(t *testing.T) {
+	err := NewTalismanRCIgnore([]byte("fileignoreconfig:\n- filename: fixture.pem\n  expires: 2020-01-31\n- filename: danger.pem\n  expires: next week\n")).ExpiryDatesError()
+
+	if assert.Error(t, err) {
+		assert.Equal(t, `invalid expires: unable to parse the expiry date "next week" of the ignore for danger.pem, expected the format YYYY-MM-DD`, err.Error())
+	}
+	assert.NoError(t, NewTalismanRCIgnore([]byte("fileignoreconfig:\n- filename: fixture.pem\n  expires: 2020-01-31\n")).ExpiryDatesError())
+}
+
+func TestExpiredIgnoresShouldNoLongerApply(t *testing.T) {
+	addition := git_repo.NewAddition("expired.pem", []byte("secret"))
+	rc := NewTalismanRCIgnore([]byte("fileignoreconfig:\n- filename: expired.pem\n  ignore_detectors: [filecontent]\n  expires: 2000-01-01\n"))
+
+	assert.False(t, rc.Deny(addition, "filecontent"))
+}
@@ -0,0 +1,33 @@
+package detector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//QuickfixReport returns the failures and warnings of the current run in the errorformat understood by editors such as Vim and Emacs.
+//Each detection is rendered on its own line as path:line:col: severity: message. Detections about the whole file, such as its name or size, are reported at its first line.
+func (r *DetectionResults) QuickfixReport() string {
+	var result strings.Builder
+	for _, resultDetails := range r.sortedResults() {
+		details := append(append([]Details{}, resultDetails.FailureList...), resultDetails.WarningList...)
+		sort.SliceStable(details, func(i, j int) bool {
+			if details[i].Line != details[j].Line {
+				return details[i].Line < details[j].Line
+			}
+			return details[i].Column < details[j].Column
+		})
+		for _, detail := range details {
+			result.WriteString(fmt.Sprintf("%s:%d:%d: %s: %s\n", resultDetails.Filename, atLeastOne(detail.Line), atLeastOne(detail.Column), detail.Severity, strings.Join(strings.Fields(detail.Message), " ")))
+		}
+	}
+	return result.String()
+}
+
+func atLeastOne(value int) int {
+	if value < 1 {
+		return 1
+	}
+	return value
+}
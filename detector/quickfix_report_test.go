@@ -0,0 +1,38 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldReportFindingsInTheQuickfixFormat(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("config/app.yml", []byte("name: app\n  password: UnsafePassword\n"))}
+
+	NewPatternDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.Equal(t, "config/app.yml:2:3: high: Potential secret pattern : password: UnsafePassword\n", results.QuickfixReport())
+}
+
+func TestShouldReportWholeFileFindingsAtTheFirstLine(t *testing.T) {
+	results := NewDetectionResults()
+	results.Fail("b.pem", "filename", "The file name \"b.pem\" failed checks", []string{}, MediumSeverity)
+	results.WarnAt("a.txt", "filecontent", "Line 3 was not scanned", []string{}, LowSeverity, Finding{Position: Position{Line: 3, Column: 1}})
+	results.FailAt("a.txt", "filecontent", "Expected file to not to contain hex encoded texts such as:\n68656C6C6F", []string{}, MediumSeverity, Finding{Text: "68656C6C6F", Position: Position{Line: 1, Column: 5}})
+
+	assert.Equal(t, `a.txt:1:5: medium: Expected file to not to contain hex encoded texts such as: 68656C6C6F
+a.txt:3:1: low: Line 3 was not scanned
+b.pem:1:1: medium: The file name "b.pem" failed checks
+`, results.QuickfixReport())
+}
+
+func TestShouldFindThePositionOfTheTextInTheData(t *testing.T) {
+	data := []byte("first\nsecond line\nthird")
+
+	assert.Equal(t, Position{Line: 2, Column: 8}, positionOf(data, "line"))
+	assert.Equal(t, Position{Line: 1, Column: 1}, positionOf(data, "first"))
+	assert.Equal(t, Position{}, positionOf(data, "missing"))
+}
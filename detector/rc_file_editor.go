@@ -0,0 +1,163 @@
+package detector
+
+import (
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+//rcFileEditor edits the lists of a .talismanrc line by line, so that the comments and formatting of the rest of the file are preserved.
+//The yaml library does not retain comments, so the file cannot be unmarshalled, changed and marshalled again.
+type rcFileEditor struct {
+	lines []string
+}
+
+//rcListBlock represents the lines of a top level key holding a list, such as fileignoreconfig
+type rcListBlock struct {
+	keyLine    int
+	end        int
+	itemIndent string
+	entries    []rcEntryRange
+}
+
+//rcEntryRange represents the lines of a single item of a list, including the comment lines right above it
+type rcEntryRange struct {
+	start int
+	end   int
+}
+
+var listItemPattern = regexp.MustCompile(`^(\s*)- `)
+
+func newRCFileEditor(contents []byte) *rcFileEditor {
+	return &rcFileEditor{strings.Split(string(contents), "\n")}
+}
+
+func (e *rcFileEditor) contents() []byte {
+	return []byte(strings.Join(e.lines, "\n"))
+}
+
+func isBlankOrComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+//listBlock returns the block of the list held by the given top level key, or nil if the file has no such key
+func (e *rcFileEditor) listBlock(key string) *rcListBlock {
+	keyPattern := regexp.MustCompile("^" + regexp.QuoteMeta(key) + `\s*:\s*(#.*)?$`)
+	keyLine := -1
+	for i, line := range e.lines {
+		if keyPattern.MatchString(line) {
+			keyLine = i
+			break
+		}
+	}
+	if keyLine == -1 {
+		return nil
+	}
+	end := keyLine + 1
+	for i := keyLine + 1; i < len(e.lines); i++ {
+		line := e.lines[i]
+		if isBlankOrComment(line) {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") && !strings.HasPrefix(line, "- ") {
+			break
+		}
+		end = i + 1
+	}
+	block := &rcListBlock{keyLine: keyLine, end: end}
+	for i := keyLine + 1; i < end; i++ {
+		match := listItemPattern.FindStringSubmatch(e.lines[i])
+		if match == nil || (len(block.entries) > 0 && match[1] != block.itemIndent) {
+			continue
+		}
+		block.itemIndent = match[1]
+		start := i
+		for start > keyLine+1 && strings.HasPrefix(strings.TrimSpace(e.lines[start-1]), "#") {
+			start--
+		}
+		if len(block.entries) > 0 {
+			block.entries[len(block.entries)-1].end = start
+		} else {
+			start = keyLine + 1
+		}
+		block.entries = append(block.entries, rcEntryRange{start, end})
+	}
+	return block
+}
+
+//entryLines returns the lines of the entry, without the indentation of the list
+func (e *rcFileEditor) entryLines(block *rcListBlock, entry rcEntryRange) []string {
+	var lines []string
+	for _, line := range e.lines[entry.start:entry.end] {
+		lines = append(lines, strings.TrimPrefix(line, block.itemIndent))
+	}
+	return lines
+}
+
+//fileIgnoreConfigOf parses the single file ignore held by the entry
+func (e *rcFileEditor) fileIgnoreConfigOf(block *rcListBlock, entry rcEntryRange) (FileIgnoreConfig, error) {
+	var configs []FileIgnoreConfig
+	err := yaml.Unmarshal([]byte(strings.Join(e.entryLines(block, entry), "\n")), &configs)
+	if err != nil || len(configs) == 0 {
+		return FileIgnoreConfig{}, err
+	}
+	return configs[0], nil
+}
+
+//replaceEntryValue replaces the value of the given key of the entry, keeping the comments on the same line.
+//It answers false if the entry does not set the key.
+func (e *rcFileEditor) replaceEntryValue(entry rcEntryRange, key string, value string) bool {
+	keyPattern := regexp.MustCompile(`^(\s*(?:-\s+)?` + regexp.QuoteMeta(key) + `\s*:\s*)([^#\s]*)(.*)$`)
+	for i := entry.start; i < entry.end; i++ {
+		if match := keyPattern.FindStringSubmatch(e.lines[i]); match != nil {
+			e.lines[i] = match[1] + value + match[3]
+			return true
+		}
+	}
+	return false
+}
+
+//removeEntries removes the given entries of the block, returning their lines without the indentation of the list
+func (e *rcFileEditor) removeEntries(block *rcListBlock, entries []rcEntryRange) [][]string {
+	var removed [][]string
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		removed = append([][]string{e.entryLines(block, entry)}, removed...)
+		e.lines = append(e.lines[:entry.start], e.lines[entry.end:]...)
+	}
+	return removed
+}
+
+//appendToList adds the given entries at the end of the list held by the key, creating the key at the end of the file if needed
+func (e *rcFileEditor) appendToList(key string, entries [][]string, defaultIndent string) {
+	if len(entries) == 0 {
+		return
+	}
+	emptyListPattern := regexp.MustCompile("^" + regexp.QuoteMeta(key) + `\s*:\s*\[\s*\]\s*$`)
+	for i, line := range e.lines {
+		if emptyListPattern.MatchString(line) {
+			e.lines[i] = key + ":"
+		}
+	}
+	block := e.listBlock(key)
+	if block == nil {
+		for len(e.lines) > 0 && strings.TrimSpace(e.lines[len(e.lines)-1]) == "" {
+			e.lines = e.lines[:len(e.lines)-1]
+		}
+		e.lines = append(e.lines, key+":", "")
+		block = e.listBlock(key)
+	}
+	indent := block.itemIndent
+	if len(block.entries) == 0 {
+		indent = defaultIndent
+	}
+	var inserted []string
+	for _, entry := range entries {
+		for _, line := range entry {
+			inserted = append(inserted, indent+line)
+		}
+	}
+	e.lines = append(e.lines[:block.end], append(inserted, e.lines[block.end:]...)...)
+}
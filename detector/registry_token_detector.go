@@ -0,0 +1,102 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//envReferencePattern matches values that only refer to an environment variable, such as ${NPM_TOKEN} or $NPM_TOKEN
+var envReferencePattern = regexp.MustCompile(`^\$(\{[A-Za-z_][A-Za-z0-9_]*\}|[A-Za-z_][A-Za-z0-9_]*)$`)
+
+//registryConfig describes a package registry config file format and the credential fields it may contain
+type registryConfig struct {
+	pathPattern   *regexp.Regexp
+	fieldPatterns []*regexp.Regexp
+}
+
+//RegistryTokenDetector flags populated auth tokens and passwords in npm, pip and gem/bundler registry config files
+type RegistryTokenDetector struct {
+	configs []registryConfig
+}
+
+//NewRegistryTokenDetector returns a RegistryTokenDetector that knows about .npmrc, .pypirc, bundler config and gem credentials
+func NewRegistryTokenDetector() *RegistryTokenDetector {
+	return &RegistryTokenDetector{[]registryConfig{
+		newRegistryConfig("(^|/)\\.?npmrc$",
+			"(?i)^\\s*(?:\\S*:)?(_authToken|_auth|_password)\\s*=\\s*(.*)$"),
+		newRegistryConfig("(^|/)\\.?pypirc$",
+			"(?i)^\\s*(password)\\s*[=:]\\s*(.*)$"),
+		newRegistryConfig("(^|/)\\.bundle/config$",
+			"^\\s*(BUNDLE_[A-Z0-9_]+__[A-Z0-9_]+)\\s*:\\s*(.*)$"),
+		newRegistryConfig("(^|/)\\.gem/credentials$",
+			"^\\s*(:?[a-z_]*api_key)\\s*:\\s*(.*)$"),
+	}}
+}
+
+func newRegistryConfig(pathPattern string, fieldPatterns ...string) registryConfig {
+	var patterns = make([]*regexp.Regexp, len(fieldPatterns))
+	for i, p := range fieldPatterns {
+		patterns[i] = regexp.MustCompile(p)
+	}
+	return registryConfig{regexp.MustCompile(pathPattern), patterns}
+}
+
+//Test tests the contents of registry config Additions to ensure that they don't contain literal credentials
+func (rd *RegistryTokenDetector) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	for _, addition := range additions {
+		config := rd.configFor(addition)
+		if config == nil {
+			continue
+		}
+		if ignoreConfig.Deny(addition, "filecontent") || cc.IsScanNotRequired(addition) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Ignoring addition as it was specified to be ignored.")
+			result.Ignore(addition.Path, "filecontent")
+			continue
+		}
+		for _, field := range ignoreConfig.reportableFindings(RegistryTokenDetectorName, addition.Path, config.findPopulatedFields(string(addition.Data)), result) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+				"field":    field,
+			}).Info("Failing file as it contains a package registry credential.")
+			result.FailAt(addition.Path, "filecontent", fmt.Sprintf("Expected file to not to contain package registry credentials such as: %s", field), addition.Commits, HighSeverity, findingIn(addition.Data, field))
+		}
+	}
+}
+
+func (rd *RegistryTokenDetector) configFor(addition git_repo.Addition) *registryConfig {
+	for i, config := range rd.configs {
+		if config.pathPattern.MatchString(string(addition.Path)) {
+			return &rd.configs[i]
+		}
+	}
+	return nil
+}
+
+func (config registryConfig) findPopulatedFields(content string) []string {
+	var fields []string
+	for _, line := range strings.Split(content, "\n") {
+		for _, pattern := range config.fieldPatterns {
+			match := pattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			if isPopulatedCredential(match[2]) {
+				fields = append(fields, strings.TrimSpace(line))
+			}
+		}
+	}
+	return fields
+}
+
+func isPopulatedCredential(value string) bool {
+	value = strings.Trim(strings.TrimSpace(value), "\"'")
+	return !isEmptyString(value) && !envReferencePattern.MatchString(value)
+}
@@ -0,0 +1,46 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldFlagLiteralAuthTokenInNpmrc(t *testing.T) {
+	results := NewDetectionResults()
+	content := []byte("registry=https://registry.npmjs.org/\n//registry.npmjs.org/:_authToken=d4a1c9f2-63be-4bb7-9a3e-3f8c2e1b7a90\n")
+	additions := []git_repo.Addition{git_repo.NewAddition(".npmrc", content)}
+
+	NewRegistryTokenDetector().Test(additions, TalismanRCIgnore{}, results)
+	assert.True(t, results.HasFailures(), "Expected .npmrc with a literal auth token to fail")
+	assert.Equal(t, "Expected file to not to contain package registry credentials such as: //registry.npmjs.org/:_authToken=d4a1c9f2-63be-4bb7-9a3e-3f8c2e1b7a90", getFailureMessages(results, additions[0].Path)[0])
+}
+
+func TestShouldNotFlagEnvironmentReferenceInNpmrc(t *testing.T) {
+	results := NewDetectionResults()
+	content := []byte("//registry.npmjs.org/:_authToken=${NPM_TOKEN}\n")
+	additions := []git_repo.Addition{git_repo.NewAddition(".npmrc", content)}
+
+	NewRegistryTokenDetector().Test(additions, TalismanRCIgnore{}, results)
+	assert.False(t, results.HasFailures(), "Expected .npmrc referring to an environment variable to pass")
+}
+
+func TestShouldFlagPasswordInPypirc(t *testing.T) {
+	results := NewDetectionResults()
+	content := []byte("[distutils]\nindex-servers = pypi\n\n[pypi]\nusername = someone\npassword = s3cr3t-pypi-pass\n")
+	additions := []git_repo.Addition{git_repo.NewAddition("home/.pypirc", content)}
+
+	NewRegistryTokenDetector().Test(additions, TalismanRCIgnore{}, results)
+	assert.True(t, results.HasFailures(), "Expected .pypirc with a password to fail")
+}
+
+func TestShouldNotScanFilesThatAreNotRegistryConfigs(t *testing.T) {
+	results := NewDetectionResults()
+	content := []byte("_authToken=d4a1c9f2-63be-4bb7-9a3e-3f8c2e1b7a90\n")
+	additions := []git_repo.Addition{git_repo.NewAddition("notes.txt", content)}
+
+	NewRegistryTokenDetector().Test(additions, TalismanRCIgnore{}, results)
+	assert.False(t, results.HasFailures(), "Expected only registry config files to be scanned")
+}
@@ -0,0 +1,158 @@
+package detector
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"talisman/git_repo"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//GroupBy represents the way in which the detection results are organized in the reports
+type GroupBy string
+
+const (
+	//GroupByFile groups the results by the file they were found in. This is the default grouping
+	GroupByFile GroupBy = "file"
+	//GroupByDetector groups the results by the detector that reported them
+	GroupByDetector GroupBy = "detector"
+	//GroupBySeverity groups the results by their severity, starting with the most severe
+	GroupBySeverity GroupBy = "severity"
+)
+
+//GroupByFromString returns the GroupBy with the given name. An empty name results in the default grouping
+func GroupByFromString(name string) (GroupBy, error) {
+	switch GroupBy(name) {
+	case "", GroupByFile:
+		return GroupByFile, nil
+	case GroupByDetector, GroupBySeverity:
+		return GroupBy(name), nil
+	}
+	return "", fmt.Errorf("unknown grouping %q, expected one of file, detector or severity", name)
+}
+
+//ResultsGroup represents the failures and warnings that share the same file, detector or severity
+type ResultsGroup struct {
+	Name    string           `json:"name"`
+	Count   int              `json:"count"`
+	Results []ResultsDetails `json:"results"`
+}
+
+//GroupResults returns the failures and warnings of the current run organized by the given grouping.
+//The groups, the files within a group and the details within a file are always returned in the same order for the same results.
+func (r *DetectionResults) GroupResults(groupBy GroupBy) []ResultsGroup {
+	return r.groupResults(groupBy, true, true)
+}
+
+func (r *DetectionResults) groupResults(groupBy GroupBy, includeFailures bool, includeWarnings bool) []ResultsGroup {
+	var groups []*ResultsGroup
+	groupsByName := map[string]*ResultsGroup{}
+	severitiesByName := map[string]Severity{}
+	addDetail := func(filePath git_repo.FilePath, detail Details, isFailure bool) {
+		name := groupName(groupBy, filePath, detail)
+		group, ok := groupsByName[name]
+		if !ok {
+			group = &ResultsGroup{Name: name, Results: make([]ResultsDetails, 0)}
+			groupsByName[name] = group
+			severitiesByName[name] = detail.Severity
+			groups = append(groups, group)
+		}
+		if len(group.Results) == 0 || group.Results[len(group.Results)-1].Filename != filePath {
+			group.Results = append(group.Results, ResultsDetails{filePath, make([]Details, 0), make([]Details, 0), make([]Details, 0)})
+		}
+		resultDetails := &group.Results[len(group.Results)-1]
+		if isFailure {
+			resultDetails.FailureList = append(resultDetails.FailureList, detail)
+		} else {
+			resultDetails.WarningList = append(resultDetails.WarningList, detail)
+		}
+		group.Count++
+	}
+
+	for _, resultDetails := range r.sortedResults() {
+		if includeFailures {
+			for _, detail := range resultDetails.FailureList {
+				addDetail(resultDetails.Filename, detail, true)
+			}
+		}
+		if includeWarnings {
+			for _, detail := range resultDetails.WarningList {
+				addDetail(resultDetails.Filename, detail, false)
+			}
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groupBy == GroupBySeverity {
+			return severitiesByName[groups[i].Name] > severitiesByName[groups[j].Name]
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	result := make([]ResultsGroup, len(groups))
+	for i, group := range groups {
+		result[i] = *group
+	}
+	return result
+}
+
+func (r *DetectionResults) sortedResults() []ResultsDetails {
+	sorted := make([]ResultsDetails, len(r.Results))
+	copy(sorted, r.Results)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Filename < sorted[j].Filename
+	})
+	return sorted
+}
+
+func groupName(groupBy GroupBy, filePath git_repo.FilePath, detail Details) string {
+	switch groupBy {
+	case GroupByDetector:
+		return detail.Category
+	case GroupBySeverity:
+		return detail.Severity.String()
+	}
+	return string(filePath)
+}
+
+//renderGroups renders the given groups as tables. Results grouped by file are rendered as a single table, as the file is already a column of it.
+func renderGroups(groups []ResultsGroup, groupBy GroupBy, header string, details func(ResultsDetails) []Details) string {
+	if groupBy == GroupByFile {
+		var data [][]string
+		for _, group := range groups {
+			data = append(data, tableRows(group, details)...)
+		}
+		return renderTable(header, data)
+	}
+	var result string
+	for _, group := range groups {
+		result = result + fmt.Sprintf("\n%s (%d)\n", group.Name, group.Count)
+		result = result + renderTable(header, tableRows(group, details))
+	}
+	return result
+}
+
+func tableRows(group ResultsGroup, details func(ResultsDetails) []Details) [][]string {
+	var data [][]string
+	for _, resultDetails := range group.Results {
+		for _, detail := range details(resultDetails) {
+			message := detail.Message
+			if runes := []rune(message); len(runes) > 150 {
+				message = string(runes[:150]) + "\n" + string(runes[150:])
+			}
+			data = append(data, []string{string(resultDetails.Filename), message})
+		}
+	}
+	return data
+}
+
+func renderTable(header string, data [][]string) string {
+	var buffer bytes.Buffer
+	table := tablewriter.NewWriter(&buffer)
+	table.SetHeader([]string{"File", header})
+	table.SetRowLine(true)
+	table.AppendBulk(data)
+	table.Render()
+	return buffer.String()
+}
@@ -0,0 +1,93 @@
+package detector
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func groupingFixture() *DetectionResults {
+	results := NewDetectionResults()
+	results.Fail("b.pem", "filename", "The file name \"b.pem\" failed checks", []string{}, MediumSeverity)
+	results.Fail("b.pem", "filecontent", "Potential secret pattern : password=b", []string{}, HighSeverity)
+	results.Fail("a.txt", "filecontent", "Expected file to not to contain hex encoded texts such as: a", []string{}, MediumSeverity)
+	results.Fail("c.bin", "filesize", "The file name \"c.bin\" is too large", []string{}, LowSeverity)
+	return results
+}
+
+func TestShouldGroupResultsByFileInFileOrder(t *testing.T) {
+	groups := groupingFixture().GroupResults(GroupByFile)
+
+	assert.Equal(t, []string{"a.txt", "b.pem", "c.bin"}, groupNames(groups))
+	assert.Equal(t, []int{1, 2, 1}, groupCounts(groups))
+}
+
+func TestShouldGroupResultsByDetector(t *testing.T) {
+	groups := groupingFixture().GroupResults(GroupByDetector)
+
+	assert.Equal(t, []string{"filecontent", "filename", "filesize"}, groupNames(groups))
+	assert.Equal(t, []int{2, 1, 1}, groupCounts(groups))
+	assert.Equal(t, "a.txt", string(groups[0].Results[0].Filename), "Expected files within a group to be ordered by path")
+	assert.Equal(t, "b.pem", string(groups[0].Results[1].Filename), "Expected files within a group to be ordered by path")
+}
+
+func TestShouldGroupResultsBySeverityStartingWithTheMostSevere(t *testing.T) {
+	groups := groupingFixture().GroupResults(GroupBySeverity)
+
+	assert.Equal(t, []string{"high", "medium", "low"}, groupNames(groups))
+	assert.Equal(t, []int{1, 2, 1}, groupCounts(groups))
+}
+
+func TestReportShouldRenderASectionPerGroup(t *testing.T) {
+	report := groupingFixture().ReportGroupedBy(GroupByDetector)
+
+	sections := regexp.MustCompile(`(?m)^(\w+) \((\d+)\)$`).FindAllStringSubmatch(report, -1)
+	assert.Len(t, sections, 3)
+	assert.Equal(t, []string{"filecontent (2)", "filename (1)", "filesize (1)"}, []string{sections[0][0], sections[1][0], sections[2][0]})
+}
+
+func TestReportGroupedByFileShouldRenderASingleTable(t *testing.T) {
+	report := groupingFixture().ReportGroupedBy(GroupByFile)
+
+	assert.NotRegexp(t, `(?m)^\w+ \(\d+\)$`, report, "Expected no sections when grouping by file")
+	assert.Equal(t, report, groupingFixture().Report(), "Expected the default report to be grouped by file")
+}
+
+func TestTableRowsShouldWrapLongMessagesOnRuneBoundaries(t *testing.T) {
+	results := NewDetectionResults()
+	message := strings.Repeat("é", 149) + "€€"
+	results.Fail("a.txt", "filecontent", message, []string{}, HighSeverity)
+
+	rows := tableRows(results.GroupResults(GroupByFile)[0], func(r ResultsDetails) []Details { return r.FailureList })
+
+	assert.True(t, utf8.ValidString(rows[0][1]), "Expected the wrapped message to be valid UTF-8")
+	assert.Equal(t, strings.Repeat("é", 149)+"€\n€", rows[0][1])
+}
+
+func TestShouldRejectUnknownGrouping(t *testing.T) {
+	_, err := GroupByFromString("colour")
+	assert.Error(t, err)
+
+	groupBy, err := GroupByFromString("")
+	assert.NoError(t, err)
+	assert.Equal(t, GroupByFile, groupBy)
+}
+
+func groupNames(groups []ResultsGroup) []string {
+	var names []string
+	for _, group := range groups {
+		names = append(names, group.Name)
+	}
+	return names
+}
+
+func groupCounts(groups []ResultsGroup) []int {
+	var counts []int
+	for _, group := range groups {
+		counts = append(counts, group.Count)
+	}
+	return counts
+}
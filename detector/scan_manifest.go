@@ -0,0 +1,84 @@
+package detector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"talisman/git_repo"
+
+	"gopkg.in/yaml.v2"
+)
+
+//ScanManifest records the content hashes of the files that previous runs scanned without finding anything, so that incremental runs,
+//such as those of a CI that caches the manifest, only scan the files whose content changed since.
+//The hash of the config the files were scanned with is recorded too, along with the version of talisman and its detectors,
+//as a change of any of them can make the files fail.
+type ScanManifest struct {
+	Config string            `json:"config"`
+	Files  map[string]string `json:"files"`
+}
+
+//NewScanManifest parses the contents of a manifest, an empty manifest recording no files
+func NewScanManifest(contents []byte) (ScanManifest, error) {
+	manifest := ScanManifest{}
+	if len(contents) > 0 {
+		if err := json.Unmarshal(contents, &manifest); err != nil {
+			return ScanManifest{}, fmt.Errorf("invalid manifest: %v", err)
+		}
+	}
+	if manifest.Files == nil {
+		manifest.Files = map[string]string{}
+	}
+	return manifest, nil
+}
+
+//Changed returns the additions whose content differs from the one recorded in the manifest, or all of them if the config
+//or the version of talisman changed
+func (m ScanManifest) Changed(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, version string) []git_repo.Addition {
+	if m.Config != configHash(ignoreConfig, version) {
+		return additions
+	}
+	var changed []git_repo.Addition
+	for _, addition := range additions {
+		if m.Files[string(addition.Path)] != contentHash(addition) {
+			changed = append(changed, addition)
+		}
+	}
+	return changed
+}
+
+//Record records the hashes of the scanned additions without failures, and forgets those of the additions with failures so that they are scanned again
+func (m *ScanManifest) Record(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, version string, results *DetectionResults) {
+	if config := configHash(ignoreConfig, version); m.Config != config {
+		m.Config = config
+		m.Files = map[string]string{}
+	}
+	for _, addition := range additions {
+		if len(results.GetFailures(addition.Path)) > 0 {
+			delete(m.Files, string(addition.Path))
+		} else {
+			m.Files[string(addition.Path)] = contentHash(addition)
+		}
+	}
+}
+
+//Contents renders the manifest as JSON, with the files sorted by path so that the manifests of identical runs are identical
+func (m ScanManifest) Contents() ([]byte, error) {
+	return json.MarshalIndent(m, "", "  ")
+}
+
+func contentHash(addition git_repo.Addition) string {
+	hash := sha256.Sum256(addition.Data)
+	return hex.EncodeToString(hash[:])
+}
+
+//configHash hashes the config along with the version of talisman and the names of its detectors, so that an upgrade rescans every file
+func configHash(ignoreConfig TalismanRCIgnore, version string) string {
+	config, _ := yaml.Marshal(ignoreConfig)
+	scanner := fmt.Sprintf("%s\n%s\n", version, strings.Join(DefaultChain().DetectorNames(), ","))
+	hash := sha256.Sum256(append([]byte(scanner), config...))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,66 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanManifestShouldOnlyReturnTheAdditionsThatChanged(t *testing.T) {
+	manifest, _ := NewScanManifest(nil)
+	unchanged := git_repo.NewAddition("unchanged.txt", []byte("same contents"))
+	changed := git_repo.NewAddition("changed.txt", []byte("old contents"))
+	manifest.Record([]git_repo.Addition{unchanged, changed}, TalismanRCIgnore{}, "1.0.0", NewDetectionResults())
+
+	changed = git_repo.NewAddition("changed.txt", []byte("new contents"))
+	added := git_repo.NewAddition("added.txt", []byte("contents"))
+
+	assert.Equal(t, []git_repo.Addition{changed, added}, manifest.Changed([]git_repo.Addition{unchanged, changed, added}, TalismanRCIgnore{}, "1.0.0"))
+}
+
+func TestScanManifestShouldForgetTheAdditionsWithFailures(t *testing.T) {
+	manifest, _ := NewScanManifest(nil)
+	secret := git_repo.NewAddition("private.pem", []byte("secret"))
+	manifest.Record([]git_repo.Addition{secret}, TalismanRCIgnore{}, "1.0.0", NewDetectionResults())
+	results := NewDetectionResults()
+	results.Fail("private.pem", "filename", "The file name \"private.pem\" failed checks", []string{}, HighSeverity)
+
+	manifest.Record([]git_repo.Addition{secret}, TalismanRCIgnore{}, "1.0.0", results)
+
+	assert.Empty(t, manifest.Files, "Expected the failing file to be forgotten")
+	assert.Equal(t, []git_repo.Addition{secret}, manifest.Changed([]git_repo.Addition{secret}, TalismanRCIgnore{}, "1.0.0"), "Expected the failing file to be scanned again")
+}
+
+func TestScanManifestShouldRescanEverythingWhenTheConfigChanges(t *testing.T) {
+	manifest, _ := NewScanManifest(nil)
+	addition := git_repo.NewAddition("notes.txt", []byte("contents"))
+	manifest.Record([]git_repo.Addition{addition}, TalismanRCIgnore{}, "1.0.0", NewDetectionResults())
+	changedConfig := NewTalismanRCIgnore([]byte("max_line_length: 100\n"))
+
+	assert.Empty(t, manifest.Changed([]git_repo.Addition{addition}, TalismanRCIgnore{}, "1.0.0"))
+	assert.Equal(t, []git_repo.Addition{addition}, manifest.Changed([]git_repo.Addition{addition}, changedConfig, "1.0.0"))
+}
+
+func TestScanManifestShouldRescanEverythingWhenTheVersionChanges(t *testing.T) {
+	manifest, _ := NewScanManifest(nil)
+	addition := git_repo.NewAddition("notes.txt", []byte("contents"))
+	manifest.Record([]git_repo.Addition{addition}, TalismanRCIgnore{}, "1.0.0", NewDetectionResults())
+
+	assert.Equal(t, []git_repo.Addition{addition}, manifest.Changed([]git_repo.Addition{addition}, TalismanRCIgnore{}, "1.1.0"))
+}
+
+func TestScanManifestShouldRoundTripThroughItsContents(t *testing.T) {
+	manifest, _ := NewScanManifest(nil)
+	manifest.Record([]git_repo.Addition{git_repo.NewAddition("notes.txt", []byte("contents"))}, TalismanRCIgnore{}, "1.0.0", NewDetectionResults())
+
+	contents, err := manifest.Contents()
+	assert.NoError(t, err)
+	read, err := NewScanManifest(contents)
+	assert.NoError(t, err)
+	assert.Equal(t, manifest, read)
+
+	_, err = NewScanManifest([]byte("not json"))
+	assert.EqualError(t, err, "invalid manifest: invalid character 'o' in literal null (expecting 'u')")
+}
@@ -0,0 +1,85 @@
+package detector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	//SkippedOutOfScope is the reason of the files left out by the scopeconfig of the .talismanrc
+	SkippedOutOfScope = "scope"
+	//SkippedIgnored is the reason of the files ignored by the .talismanrc for both their name and their content
+	SkippedIgnored = "ignored"
+	//SkippedChecksum is the reason of the files whose checksum matches the one recorded in the .talismanrc
+	SkippedChecksum = "checksum"
+	//SkippedUnchanged is the reason of the files whose content matches the one recorded in the manifest of a previous run
+	SkippedUnchanged = "unchanged"
+)
+
+//ScanStats summarizes what a run of the detectors scanned, without any detail of what they found
+type ScanStats struct {
+	FilesScanned   int             `json:"files_scanned"`
+	FilesSkipped   map[string]int  `json:"files_skipped"`
+	BytesProcessed int64           `json:"bytes_processed"`
+	Detectors      []DetectorStats `json:"detectors"`
+}
+
+//DetectorStats records how long a detector took to scan the additions
+type DetectorStats struct {
+	Name    string        `json:"name"`
+	Elapsed time.Duration `json:"elapsed_ns"`
+}
+
+//Skip records that count files were not scanned for the given reason
+func (s *ScanStats) Skip(reason string, count int) {
+	if count == 0 {
+		return
+	}
+	if s.FilesSkipped == nil {
+		s.FilesSkipped = map[string]int{}
+	}
+	s.FilesSkipped[reason] += count
+}
+
+//WithoutTimings clears the time taken by each detector, which varies from run to run, so that the stats of identical inputs are identical
+func (s *ScanStats) WithoutTimings() {
+	for i := range s.Detectors {
+		s.Detectors[i].Elapsed = 0
+	}
+}
+
+//TotalSkipped returns the number of files that were not scanned, whatever the reason
+func (s ScanStats) TotalSkipped() int {
+	total := 0
+	for _, count := range s.FilesSkipped {
+		total += count
+	}
+	return total
+}
+
+//Report renders the stats as text meant to be read in a terminal
+func (s ScanStats) Report() string {
+	var reasons []string
+	for reason := range s.FilesSkipped {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	var skipped []string
+	for _, reason := range reasons {
+		skipped = append(skipped, fmt.Sprintf("%s: %d", reason, s.FilesSkipped[reason]))
+	}
+	var report strings.Builder
+	fmt.Fprintf(&report, "Files scanned:   %d\n", s.FilesScanned)
+	fmt.Fprintf(&report, "Files skipped:   %d", s.TotalSkipped())
+	if len(skipped) > 0 {
+		fmt.Fprintf(&report, " (%s)", strings.Join(skipped, ", "))
+	}
+	fmt.Fprintf(&report, "\nBytes processed: %d\n", s.BytesProcessed)
+	fmt.Fprintf(&report, "Detectors run:   %d\n", len(s.Detectors))
+	for _, d := range s.Detectors {
+		fmt.Fprintf(&report, "  %-16s %s\n", d.Name, d.Elapsed)
+	}
+	return report.String()
+}
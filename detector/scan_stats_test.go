@@ -0,0 +1,84 @@
+package detector
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainShouldRecordTheStatsOfTheAdditionsItScanned(t *testing.T) {
+	v := NewChain()
+	v.AddNamedDetector("passing", "filecontent", PassingDetection{})
+	v.AddNamedDetector("naming", "filename", PassingDetection{})
+	results := NewDetectionResults()
+	ignores := NewTalismanRCIgnore([]byte("fileignoreconfig:\n- filename: 'images/*'\n  ignore_detectors: [filename, filecontent]\n"))
+	additions := []git_repo.Addition{
+		git_repo.NewAddition("a.txt", []byte("contents")),
+		git_repo.NewAddition("b.txt", []byte("more contents")),
+		git_repo.NewAddition("c.txt", []byte("")),
+		git_repo.NewAddition("images/logo.png", []byte{0x89, 'P', 'N', 'G', 0x00}),
+		git_repo.NewAddition("images/icon.png", []byte{0x89, 'P', 'N', 'G', 0x00}),
+	}
+	v.Test(additions, ignores, results)
+
+	stats := results.Stats()
+	assert.Equal(t, 3, stats.FilesScanned)
+	assert.Equal(t, map[string]int{SkippedIgnored: 2}, stats.FilesSkipped)
+	assert.Equal(t, int64(21), stats.BytesProcessed)
+	assert.Equal(t, 2, len(stats.Detectors))
+	assert.Equal(t, "passing", stats.Detectors[0].Name)
+	assert.Equal(t, "naming", stats.Detectors[1].Name)
+}
+
+func TestStatsShouldNotRecordTheDetectorsThatDidNotExecute(t *testing.T) {
+	v := NewChain()
+	v.AddNamedDetector("passing", "filecontent", PassingDetection{})
+	v.AddNamedDetector("naming", "filename", PassingDetection{})
+	results := NewDetectionResults()
+	ignores := NewTalismanRCIgnore([]byte("fileignoreconfig:\n- filename: '*'\n  ignore_detectors: [filecontent]\n"))
+	v.Test([]git_repo.Addition{git_repo.NewAddition("a.txt", []byte("contents"))}, ignores, results)
+
+	stats := results.Stats()
+	assert.Equal(t, 1, stats.FilesScanned, "Expected a file that is only ignored for some detectors to be scanned")
+	assert.Equal(t, 1, len(stats.Detectors))
+	assert.Equal(t, "naming", stats.Detectors[0].Name)
+}
+
+func TestStatsReportShouldListTheSkippedFilesByReason(t *testing.T) {
+	stats := ScanStats{FilesScanned: 4, BytesProcessed: 120, Detectors: []DetectorStats{{"filename", 0}}}
+	stats.Skip(SkippedOutOfScope, 2)
+	stats.Skip(SkippedIgnored, 1)
+	stats.Skip(SkippedChecksum, 0)
+
+	report := stats.Report()
+	assert.Contains(t, report, "Files scanned:   4\n")
+	assert.Contains(t, report, "Files skipped:   3 (ignored: 1, scope: 2)\n")
+	assert.Contains(t, report, "Bytes processed: 120\n")
+	assert.Contains(t, report, "Detectors run:   1\n")
+	assert.Contains(t, report, "filename")
+}
+
+func TestStatsShouldRenderAsJSON(t *testing.T) {
+	stats := ScanStats{FilesScanned: 1, BytesProcessed: 8}
+	stats.Skip(SkippedOutOfScope, 1)
+
+	output, err := json.Marshal(stats)
+	assert.Nil(t, err)
+	var parsed map[string]interface{}
+	assert.Nil(t, json.Unmarshal(output, &parsed))
+	assert.Equal(t, float64(1), parsed["files_scanned"])
+	assert.Equal(t, map[string]interface{}{"scope": float64(1)}, parsed["files_skipped"])
+	assert.Equal(t, float64(8), parsed["bytes_processed"])
+}
+
+func TestWithoutTimingsShouldClearTheTimeTakenByTheDetectors(t *testing.T) {
+	stats := ScanStats{Detectors: []DetectorStats{{"filename", 3 * time.Millisecond}, {"pattern", 5 * time.Millisecond}}}
+
+	stats.WithoutTimings()
+
+	assert.Equal(t, []DetectorStats{{"filename", 0}, {"pattern", 0}}, stats.Detectors)
+}
@@ -0,0 +1,64 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//Severity represents how damaging a detection is likely to be if it were to leave the workstation
+type Severity int
+
+const (
+	//LowSeverity is used for detections that are suspicious but rarely contain a usable secret
+	LowSeverity Severity = iota + 1
+	//MediumSeverity is used for detections that may contain a secret
+	MediumSeverity
+	//HighSeverity is used for detections that are likely to contain a secret
+	HighSeverity
+	//CriticalSeverity is used for detections that are known to grant access to production systems
+	CriticalSeverity
+)
+
+var severityNames = map[Severity]string{
+	LowSeverity:      "low",
+	MediumSeverity:   "medium",
+	HighSeverity:     "high",
+	CriticalSeverity: "critical",
+}
+
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+//SeverityFromString returns the Severity with the given name, ignoring case
+func SeverityFromString(name string) (Severity, error) {
+	for severity, severityName := range severityNames {
+		if strings.EqualFold(severityName, strings.TrimSpace(name)) {
+			return severity, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q, expected one of low, medium, high or critical", name)
+}
+
+//MarshalJSON renders the Severity by its name
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+//UnmarshalJSON reads the Severity from its name
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	severity, err := SeverityFromString(name)
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
@@ -0,0 +1,82 @@
+package detector
+
+import (
+	"fmt"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const (
+	//FailAction fails the run on the findings of a severity, which is what happens to the severities without an action
+	FailAction = "fail"
+	//WarnAction reports the failures of a severity as warnings, which do not fail the run
+	WarnAction = "warn"
+	//IgnoreAction ignores the failures of a severity, marking their files as ignored instead
+	IgnoreAction = "ignore"
+)
+
+//SeverityActions maps the severities to the action taken on their findings, so that for instance critical findings fail the run while high ones only warn
+type SeverityActions map[Severity]string
+
+//SeverityActions parses the severity_actions of the config, failing on the first unknown severity or action
+func (ignore TalismanRCIgnore) SeverityActions() (SeverityActions, error) {
+	actions := SeverityActions{}
+	for name, action := range ignore.SeverityActionConfig {
+		severity, err := SeverityFromString(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid severity_actions: %v", err)
+		}
+		if action != FailAction && action != WarnAction && action != IgnoreAction {
+			return nil, fmt.Errorf("invalid severity_actions: unknown action %q for %s, expected one of fail, warn or ignore", action, severity)
+		}
+		actions[severity] = action
+	}
+	return actions, nil
+}
+
+//actionFor returns the action taken on the findings of the severity, failing the run unless configured otherwise
+func (a SeverityActions) actionFor(severity Severity) string {
+	if action, ok := a[severity]; ok {
+		return action
+	}
+	return FailAction
+}
+
+func mergeSeverityActions(actions map[string]string, other map[string]string) map[string]string {
+	if len(actions) == 0 && len(other) == 0 {
+		return nil
+	}
+	result := map[string]string{}
+	for name, action := range actions {
+		result[name] = action
+	}
+	for name, action := range other {
+		result[name] = action
+	}
+	return result
+}
+
+//isActedUpon applies the action configured for the severity of a finding, answering true if the finding was warned about or ignored instead of failed.
+//Malformed severity actions are left out of the results, so every finding fails.
+func (r *DetectionResults) isActedUpon(filePath git_repo.FilePath, category string, message string, commits []string, severity Severity, finding Finding) bool {
+	switch r.severityActions.actionFor(severity) {
+	case WarnAction:
+		log.WithFields(log.Fields{
+			"filePath": filePath,
+			"severity": severity,
+		}).Info("Warning file instead of failing it as configured by the severity actions.")
+		r.WarnAt(filePath, category, message, commits, severity, finding)
+		return true
+	case IgnoreAction:
+		log.WithFields(log.Fields{
+			"filePath": filePath,
+			"severity": severity,
+		}).Info("Ignoring finding as configured by the severity actions.")
+		r.auditFinding(AuditSuppressed, "", filePath, category, message, severity, finding, "severity_actions", severity.String()+": "+IgnoreAction)
+		r.Ignore(filePath, category)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//shellFilePathPattern matches the history files of the common shells and the rc files they run at startup
+var shellFilePathPattern = regexp.MustCompile(`(^|/)\.((bash|zsh|sh|ksh)_history|zhistory|bashrc|bash_profile|bash_login|bash_aliases|zshrc|zshenv|zprofile|kshrc|profile)$`)
+
+//shellExportPattern matches the variables exported by a command, keeping the name and value of the variable as the finding
+var shellExportPattern = regexp.MustCompile(`(?:^|[\s;&|])(?:export|declare\s+-x)\s+(([A-Za-z_][A-Za-z0-9_]*)=("[^"]*"|'[^']*'|[^\s;&|]+))`)
+
+//shellPasswordFlagPattern matches the long command line options that take a credential, such as --password hunter2 or --token=abc
+var shellPasswordFlagPattern = regexp.MustCompile(`(?:^|\s)(--(?:password|passwd|pass|token|api-key|secret|client-secret)(?:=|\s+)("[^"]*"|'[^']*'|[^\s;&|]+))`)
+
+//shellShortPasswordFlag matches the -p option of the commands that take a password with it, as -p is a port or a parent directory for most other commands
+type shellShortPasswordFlag struct {
+	command *regexp.Regexp
+	flag    *regexp.Regexp
+}
+
+var shellShortPasswordFlags = []shellShortPasswordFlag{
+	{regexp.MustCompile(`(?:^|[\s;&|])(?:sshpass|(?:docker|podman|buildah|skopeo|helm\s+registry)\s+login)\s`), regexp.MustCompile(`(?:^|\s)(-p\s+("[^"]*"|'[^']*'|[^\s;&|]+))`)},
+	{regexp.MustCompile(`(?:^|[\s;&|])(?:mysql|mysqldump|mysqladmin|mariadb)\s`), regexp.MustCompile(`(?:^|\s)(-p("[^"]*"|'[^']*'|[^\s;&|]+))`)},
+}
+
+//ShellHistoryDetector flags the secrets that shell history and rc files hold, as exported variables or as the credentials passed to commands
+type ShellHistoryDetector struct{}
+
+//NewShellHistoryDetector returns a ShellHistoryDetector
+func NewShellHistoryDetector() *ShellHistoryDetector {
+	return &ShellHistoryDetector{}
+}
+
+//Test tests the shell history and rc files among the Additions to ensure that they don't hold secrets
+func (sd *ShellHistoryDetector) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	for _, addition := range additions {
+		if !shellFilePathPattern.MatchString(string(addition.Path)) {
+			continue
+		}
+		if ignoreConfig.Deny(addition, "filecontent") || cc.IsScanNotRequired(addition) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Ignoring addition as it was specified to be ignored.")
+			result.Ignore(addition.Path, "filecontent")
+			continue
+		}
+		for _, finding := range ignoreConfig.reportableFindings(ShellHistoryDetectorName, addition.Path, shellSecrets(string(addition.Data)), result) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Failing file as it holds a secret in a shell history or rc file.")
+			result.FailAt(addition.Path, "filecontent", fmt.Sprintf("Expected shell file to not to contain secrets such as: %s", finding), addition.Commits, HighSeverity, findingIn(addition.Data, finding))
+		}
+	}
+}
+
+//shellSecrets returns the exports of secret-named variables and the credential options of the commands that hold real values.
+//References such as ${TOKEN} and command substitutions such as $(pass show token) are not secrets written in the file.
+func shellSecrets(content string) []string {
+	var findings []string
+	for _, line := range strings.Split(content, "\n") {
+		for _, match := range shellExportPattern.FindAllStringSubmatch(line, -1) {
+			if envSecretNamePattern.MatchString(match[2]) && isShellCredential(match[3]) {
+				findings = append(findings, match[1])
+			}
+		}
+		for _, match := range shellPasswordFlagPattern.FindAllStringSubmatch(line, -1) {
+			if isShellCredential(match[2]) {
+				findings = append(findings, match[1])
+			}
+		}
+		for _, short := range shellShortPasswordFlags {
+			if !short.command.MatchString(line) {
+				continue
+			}
+			for _, match := range short.flag.FindAllStringSubmatch(line, -1) {
+				if isShellCredential(match[2]) {
+					findings = append(findings, match[1])
+				}
+			}
+		}
+	}
+	return findings
+}
+
+func isShellCredential(value string) bool {
+	value = strings.Trim(value, "\"'")
+	return isPopulatedCredential(value) && !strings.HasPrefix(value, "$(") && !strings.HasPrefix(value, "`")
+}
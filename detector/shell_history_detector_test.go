@@ -0,0 +1,82 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldFailExportedSecretsInShellFiles(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("home/.bashrc", []byte("alias ll='ls -la'\nexport TOKEN=abc\n"))}
+
+	NewShellHistoryDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	failures := results.GetFailures("home/.bashrc")
+	if assert.Len(t, failures, 1) {
+		assert.Equal(t, "Expected shell file to not to contain secrets such as: TOKEN=abc", failures[0].Message)
+		assert.Equal(t, HighSeverity, failures[0].Severity)
+		assert.Equal(t, 2, failures[0].Line)
+		assert.Equal(t, "abc", failures[0].SecretValue())
+	}
+}
+
+func TestShouldFailCredentialsPassedToCommandsInShellHistory(t *testing.T) {
+	results := NewDetectionResults()
+	content := []byte(`curl --user admin https://ci.internal --password hunter2
+: 1600000000:0;docker login -u deploy -p Dk29fmQ1zz registry.internal
+mysql -u root -pS3cr3tPass app
+mkdir -p build/output
+ssh -p 2222 host
+`)
+	additions := []git_repo.Addition{git_repo.NewAddition(".zsh_history", content)}
+
+	NewShellHistoryDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	var messages []string
+	for _, failure := range results.GetFailures(".zsh_history") {
+		messages = append(messages, failure.Message)
+	}
+	assert.Equal(t, []string{
+		"Expected shell file to not to contain secrets such as: --password hunter2",
+		"Expected shell file to not to contain secrets such as: -p Dk29fmQ1zz",
+		"Expected shell file to not to contain secrets such as: -pS3cr3tPass",
+	}, messages)
+}
+
+func TestShouldAllowEnvReferencesInShellFiles(t *testing.T) {
+	results := NewDetectionResults()
+	content := []byte(`deploy --password ${VAR}
+export API_TOKEN=$(pass show api/token)
+export GITHUB_TOKEN="$GH_TOKEN"
+`)
+	additions := []git_repo.Addition{git_repo.NewAddition(".bash_history", content)}
+
+	NewShellHistoryDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasDetectionMessages(), "Expected env references to pass")
+}
+
+func TestShouldNotFlagBenignAliasesAndExports(t *testing.T) {
+	results := NewDetectionResults()
+	content := []byte(`alias token-refresh='gcloud auth print-access-token'
+export PATH=$HOME/bin:$PATH
+export EDITOR=vim
+`)
+	additions := []git_repo.Addition{git_repo.NewAddition(".zshrc", content)}
+
+	NewShellHistoryDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasDetectionMessages(), "Expected aliases and exports of non secrets to pass")
+}
+
+func TestShouldOnlyScanShellHistoryAndRCFiles(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("docs/setup.md", []byte("export TOKEN=abc\n"))}
+
+	NewShellHistoryDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasDetectionMessages(), "Expected files other than shell history and rc files to be left alone")
+}
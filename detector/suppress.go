@@ -0,0 +1,324 @@
+package detector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"talisman/git_repo"
+
+	"github.com/bmatcuk/doublestar"
+)
+
+//SuppressRule represents a suppress expression of the .talismanrc, such as
+//
+//	detector == "base64" && path matches "test/**" && severity < high
+//
+//The expression is evaluated against every finding, and the findings it matches are ignored instead of being reported.
+//It can only compare the fields of the finding with literal values, so evaluating it never runs any code.
+type SuppressRule struct {
+	Expression string
+	condition  suppressCondition
+}
+
+//suppressedFinding holds the fields of a finding that a suppress expression can refer to
+type suppressedFinding struct {
+	detector string
+	category string
+	path     string
+	message  string
+	severity Severity
+	line     int
+}
+
+type suppressCondition interface {
+	matches(finding suppressedFinding) bool
+}
+
+type suppressAnd struct{ left, right suppressCondition }
+
+func (c suppressAnd) matches(finding suppressedFinding) bool {
+	return c.left.matches(finding) && c.right.matches(finding)
+}
+
+type suppressOr struct{ left, right suppressCondition }
+
+func (c suppressOr) matches(finding suppressedFinding) bool {
+	return c.left.matches(finding) || c.right.matches(finding)
+}
+
+type suppressNot struct{ condition suppressCondition }
+
+func (c suppressNot) matches(finding suppressedFinding) bool {
+	return !c.condition.matches(finding)
+}
+
+//suppressComparison compares a field of the finding with a literal value
+type suppressComparison struct {
+	field    string
+	operator string
+	value    string
+	number   int
+}
+
+var suppressFields = map[string]string{
+	"detector": "string",
+	"category": "string",
+	"path":     "string",
+	"message":  "string",
+	"severity": "severity",
+	"line":     "number",
+}
+
+var suppressOperators = map[string][]string{
+	"string":   {"==", "!=", "matches", "contains"},
+	"severity": {"==", "!=", "<", "<=", ">", ">="},
+	"number":   {"==", "!=", "<", "<=", ">", ">="},
+}
+
+func (c suppressComparison) matches(finding suppressedFinding) bool {
+	switch c.field {
+	case "severity":
+		return compareNumbers(int(finding.severity), c.operator, c.number)
+	case "line":
+		return compareNumbers(finding.line, c.operator, c.number)
+	}
+	actual := map[string]string{"detector": finding.detector, "category": finding.category, "path": finding.path, "message": finding.message}[c.field]
+	switch c.operator {
+	case "==":
+		return actual == c.value
+	case "!=":
+		return actual != c.value
+	case "contains":
+		return strings.Contains(actual, c.value)
+	}
+	matched, _ := doublestar.Match(c.value, actual)
+	return matched
+}
+
+func compareNumbers(actual int, operator string, expected int) bool {
+	switch operator {
+	case "==":
+		return actual == expected
+	case "!=":
+		return actual != expected
+	case "<":
+		return actual < expected
+	case "<=":
+		return actual <= expected
+	case ">":
+		return actual > expected
+	}
+	return actual >= expected
+}
+
+//ParseSuppressRule parses a suppress expression, returning an error that describes the first problem found in it
+func ParseSuppressRule(expression string) (SuppressRule, error) {
+	tokens, err := suppressTokens(expression)
+	if err != nil {
+		return SuppressRule{}, fmt.Errorf("invalid suppress expression %q: %v", expression, err)
+	}
+	parser := &suppressParser{tokens: tokens}
+	condition, err := parser.parseOr()
+	if err == nil && parser.position < len(tokens) {
+		err = fmt.Errorf("unexpected %s", tokens[parser.position])
+	}
+	if err != nil {
+		return SuppressRule{}, fmt.Errorf("invalid suppress expression %q: %v", expression, err)
+	}
+	return SuppressRule{expression, condition}, nil
+}
+
+//SuppressRules parses the suppress expressions of the config, failing on the first malformed one
+func (ignore TalismanRCIgnore) SuppressRules() ([]SuppressRule, error) {
+	var rules []SuppressRule
+	for _, expression := range ignore.Suppress {
+		rule, err := ParseSuppressRule(expression)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+//matches answers true if the expression matches the finding reported by the named detector, with the given category
+func (r SuppressRule) matches(detectorName string, filePath git_repo.FilePath, category string, message string, severity Severity, finding Finding) bool {
+	return r.condition.matches(suppressedFinding{detectorName, category, string(filePath), message, severity, finding.Line})
+}
+
+//suppressToken is either a string literal, kept with its quotes so that it can be told apart from a word, or a word or operator
+type suppressToken string
+
+func (t suppressToken) isString() bool {
+	return strings.HasPrefix(string(t), `"`)
+}
+
+func (t suppressToken) String() string {
+	if t.isString() {
+		return strconv.Quote(strings.TrimPrefix(string(t), `"`))
+	}
+	return string(t)
+}
+
+func suppressTokens(expression string) ([]suppressToken, error) {
+	var tokens []suppressToken
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			value, err := strconv.Unquote(string(runes[i : end+1]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s", string(runes[i:end+1]))
+			}
+			tokens = append(tokens, suppressToken(`"`+value))
+			i = end + 1
+		case strings.ContainsRune("()!", r) && !(r == '!' && i+1 < len(runes) && runes[i+1] == '='):
+			tokens = append(tokens, suppressToken(r))
+			i++
+		case strings.ContainsRune("=!<>&|", r):
+			end := i + 1
+			for end < len(runes) && strings.ContainsRune("=&|", runes[end]) {
+				end++
+			}
+			operator := string(runes[i:end])
+			if !contains([]string{"==", "!=", "<", "<=", ">", ">=", "&&", "||"}, operator) {
+				return nil, fmt.Errorf("unknown operator %q", operator)
+			}
+			tokens = append(tokens, suppressToken(operator))
+			i = end
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			tokens = append(tokens, suppressToken(runes[i:end]))
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+	return tokens, nil
+}
+
+//suppressParser parses the tokens of an expression by recursive descent, with && binding tighter than ||
+type suppressParser struct {
+	tokens   []suppressToken
+	position int
+}
+
+func (p *suppressParser) peek() suppressToken {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position]
+	}
+	return ""
+}
+
+func (p *suppressParser) next() (suppressToken, error) {
+	if p.position >= len(p.tokens) {
+		return "", fmt.Errorf("unexpected end of expression")
+	}
+	p.position++
+	return p.tokens[p.position-1], nil
+}
+
+func (p *suppressParser) parseOr() (suppressCondition, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.peek() == "||" {
+		p.position++
+		var right suppressCondition
+		right, err = p.parseAnd()
+		left = suppressOr{left, right}
+	}
+	return left, err
+}
+
+func (p *suppressParser) parseAnd() (suppressCondition, error) {
+	left, err := p.parseUnary()
+	for err == nil && p.peek() == "&&" {
+		p.position++
+		var right suppressCondition
+		right, err = p.parseUnary()
+		left = suppressAnd{left, right}
+	}
+	return left, err
+}
+
+func (p *suppressParser) parseUnary() (suppressCondition, error) {
+	switch p.peek() {
+	case "!":
+		p.position++
+		condition, err := p.parseUnary()
+		return suppressNot{condition}, err
+	case "(":
+		p.position++
+		condition, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing, err := p.next(); err != nil || closing != ")" {
+			return nil, fmt.Errorf("expected ) to close (")
+		}
+		return condition, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *suppressParser) parseComparison() (suppressCondition, error) {
+	field, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	fieldType, ok := suppressFields[string(field)]
+	if !ok || field.isString() {
+		return nil, fmt.Errorf("unknown field %s, expected one of detector, category, path, message, severity or line", field)
+	}
+	operator, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if !contains(suppressOperators[fieldType], string(operator)) {
+		return nil, fmt.Errorf("operator %s cannot be applied to %s, expected one of %s", operator, field, strings.Join(suppressOperators[fieldType], ", "))
+	}
+	value, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	comparison := suppressComparison{field: string(field), operator: string(operator)}
+	switch fieldType {
+	case "severity":
+		severity, err := SeverityFromString(strings.TrimPrefix(string(value), `"`))
+		if err != nil {
+			return nil, err
+		}
+		comparison.number = int(severity)
+	case "number":
+		comparison.number, err = strconv.Atoi(string(value))
+		if err != nil {
+			return nil, fmt.Errorf("expected a number to compare %s with, found %s", field, value)
+		}
+	default:
+		if !value.isString() {
+			return nil, fmt.Errorf("expected a quoted string to compare %s with, found %s", field, value)
+		}
+		comparison.value = strings.TrimPrefix(string(value), `"`)
+		if _, err := doublestar.Match(comparison.value, ""); operator == "matches" && err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %v", value, err)
+		}
+	}
+	return comparison, nil
+}
@@ -0,0 +1,88 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuppressExpressionShouldOnlyIgnoreMatchingFindings(t *testing.T) {
+	results := NewDetectionResults()
+	ignores := NewTalismanRCIgnore([]byte(`suppress:
+- detector == "pattern" && path matches "test/**" && severity <= high
+`))
+	additions := []git_repo.Addition{
+		git_repo.NewAddition("test/data/users.yml", []byte("password: testpassword")),
+		git_repo.NewAddition("config/users.yml", []byte("password: testpassword")),
+	}
+
+	NewChain().AddNamedDetector(PatternDetectorName, "filecontent", NewPatternDetector()).Test(additions, ignores, results)
+
+	assert.Len(t, results.GetFailures("test/data/users.yml"), 0, "Expected the finding in the test file to be suppressed")
+	assert.True(t, results.HasIgnores(), "Expected the suppressed finding to be reported as ignored")
+	assert.Len(t, results.GetFailures("config/users.yml"), 1, "Expected the finding outside of the test files to be reported")
+}
+
+func TestSuppressExpressionShouldMatchTheDetectorOfTheFinding(t *testing.T) {
+	results := NewDetectionResults()
+	ignores := NewTalismanRCIgnore([]byte(`suppress:
+- detector == "base64" && path matches "test/**"
+`))
+	additions := []git_repo.Addition{
+		git_repo.NewAddition("test/data/keys.txt", []byte("c2VjcmV0LWtleS10aGF0LWxvb2tzLXJlYWwtMTIz\n")),
+		git_repo.NewAddition("test/data/users.yml", []byte("password: testpassword")),
+	}
+
+	DefaultChain().Test(additions, ignores, results)
+
+	assert.Len(t, results.GetFailures("test/data/keys.txt"), 0, "Expected the base64 finding of the content detector to be suppressed")
+	assert.Len(t, results.GetFailures("test/data/users.yml"), 1, "Expected the finding of the pattern detector to be reported")
+}
+
+func TestSuppressExpressionShouldCompareSeverities(t *testing.T) {
+	finding := suppressedFinding{detector: "knowntoken", category: "filecontent", path: "a/b.go", message: "m", severity: HighSeverity, line: 3}
+	for expression, expected := range map[string]bool{
+		`severity < high`:                            false,
+		`severity <= high`:                           true,
+		`severity == "HIGH"`:                         true,
+		`!(severity > medium) || line == 3`:          true,
+		`path matches "a/*" && message contains "m"`: true,
+		`detector != "knowntoken" || line >= 4`:      false,
+		`category == "filecontent"`:                  true,
+		`detector == "filecontent"`:                  false,
+	} {
+		rule, err := ParseSuppressRule(expression)
+		assert.NoError(t, err, expression)
+		assert.Equal(t, expected, rule.condition.matches(finding), expression)
+	}
+}
+
+func TestMalformedSuppressExpressionsShouldBeReportedAsConfigErrors(t *testing.T) {
+	for expression, expected := range map[string]string{
+		`detector == "filecontent" &&`: `invalid suppress expression "detector == \"filecontent\" &&": unexpected end of expression`,
+		`owner == "me"`:                `invalid suppress expression "owner == \"me\"": unknown field owner, expected one of detector, category, path, message, severity or line`,
+		`path < "a"`:                   `invalid suppress expression "path < \"a\"": operator < cannot be applied to path, expected one of ==, !=, matches, contains`,
+		`severity > urgent`:            `invalid suppress expression "severity > urgent": unknown severity "urgent", expected one of low, medium, high or critical`,
+		`path == test`:                 `invalid suppress expression "path == test": expected a quoted string to compare path with, found test`,
+		`(line == 1`:                   `invalid suppress expression "(line == 1": expected ) to close (`,
+		`path == "a" ; rm -rf /`:       `invalid suppress expression "path == \"a\" ; rm -rf /": unexpected character ';'`,
+		`path == "a" path == "b"`:      `invalid suppress expression "path == \"a\" path == \"b\"": unexpected path`,
+	} {
+		_, err := NewTalismanRCIgnore([]byte("suppress:\n- '" + expression + "'\n")).SuppressRules()
+		if assert.Error(t, err, expression) {
+			assert.Equal(t, expected, err.Error())
+		}
+	}
+}
+
+func TestMalformedSuppressExpressionsShouldNotSuppressAnything(t *testing.T) {
+	results := NewDetectionResults()
+	ignores := NewTalismanRCIgnore([]byte("suppress:\n- 'path matches'\n"))
+	additions := []git_repo.Addition{git_repo.NewAddition("test/data/users.yml", []byte("password: testpassword"))}
+
+	NewChain().AddDetector(NewPatternDetector()).Test(additions, ignores, results)
+
+	assert.True(t, results.HasFailures(), "Expected a malformed expression to not suppress the finding")
+}
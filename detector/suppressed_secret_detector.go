@@ -0,0 +1,96 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//suppressionCommentPattern matches the comments that turn security tooling off for a line, such as # nosec, // eslint-disable-line,
+//# noqa, // NOSONAR or # pragma: allowlist secret
+var suppressionCommentPattern = regexp.MustCompile(`(?i)(?:#|//|/\*|<!--|--)\s*#?\s*(nosec|nolint|noqa|nosemgrep|nosonar|eslint-disable(?:-next-line|-line)?|gitleaks:allow|pragma:\s*allowlist\s+secret|lgtm|checkov:skip|tfsec:ignore|trufflehog:ignore)\b`)
+
+//suppressedAssignmentPattern matches the quoted literals assigned to a name, so that secret-named fields are flagged whatever their entropy
+var suppressedAssignmentPattern = regexp.MustCompile(`([A-Za-z0-9_.-]+)["']?\s*[:=]\s*["']([^"']+)["']`)
+
+//SuppressedSecretDetector flags the lines that both turn security tooling off with a comment and hold a secret-looking value,
+//as that combination often hides a real secret from the other scanners of a project.
+//A comment that turns the tooling off for the next line, such as // eslint-disable-next-line, is checked against the next line.
+//It is an opt-in detector, which only runs when enabled under detectors.suppressedsecret in the .talismanrc.
+type SuppressedSecretDetector struct {
+	base64Detector *Base64Detector
+}
+
+//NewSuppressedSecretDetector returns a SuppressedSecretDetector
+func NewSuppressedSecretDetector() *SuppressedSecretDetector {
+	return &SuppressedSecretDetector{NewBase64Detector()}
+}
+
+func (sd *SuppressedSecretDetector) isEnabled(ignoreConfig TalismanRCIgnore) bool {
+	return ignoreConfig.Detectors[SuppressedSecretDetectorName].Enabled
+}
+
+//Test tests the contents of the Additions to ensure that the lines hidden from security tooling don't hold secrets
+func (sd *SuppressedSecretDetector) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	for _, addition := range additions {
+		if ignoreConfig.Deny(addition, "filecontent") || cc.IsScanNotRequired(addition) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Ignoring addition as it was specified to be ignored.")
+			result.Ignore(addition.Path, "filecontent")
+			continue
+		}
+		for _, line := range ignoreConfig.reportableFindings(SuppressedSecretDetectorName, addition.Path, sd.suppressedSecrets(string(addition.Data)), result) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Failing file as it hides a secret from security tooling.")
+			result.FailAt(addition.Path, "filecontent", fmt.Sprintf("Expected line hidden from security tooling by a suppression comment to not to contain secrets such as: %s", line), addition.Commits, HighSeverity, findingIn(addition.Data, line))
+		}
+	}
+}
+
+//suppressedSecrets returns the lines that a suppression comment hides from security tooling and that hold a secret-looking value
+func (sd *SuppressedSecretDetector) suppressedSecrets(content string) []string {
+	var secrets []string
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		match := suppressionCommentPattern.FindStringSubmatchIndex(line)
+		if match == nil {
+			continue
+		}
+		code, suppressedLine := line[:match[0]], line
+		if strings.HasSuffix(strings.ToLower(line[match[2]:match[3]]), "-next-line") {
+			if i+1 == len(lines) {
+				continue
+			}
+			code, suppressedLine = lines[i+1], lines[i+1]
+		}
+		if sd.hasSecret(code) {
+			secrets = append(secrets, strings.TrimSpace(suppressedLine))
+		}
+	}
+	return secrets
+}
+
+//hasSecret answers true if the code holds a high entropy value, or a literal assigned to a secret-named field
+func (sd *SuppressedSecretDetector) hasSecret(code string) bool {
+	for _, match := range suppressedAssignmentPattern.FindAllStringSubmatch(code, -1) {
+		if ciSecretNamePattern.MatchString(match[1]) && len(match[2]) >= minCISecretLength && isPopulatedCredential(match[2]) {
+			return true
+		}
+	}
+	words := strings.FieldsFunc(code, func(r rune) bool {
+		return strings.ContainsRune(" \t\"'`,;()[]{}<>", r)
+	})
+	for _, word := range words {
+		if sd.base64Detector.checkBase64Encoding(word) != "" {
+			return true
+		}
+	}
+	return false
+}
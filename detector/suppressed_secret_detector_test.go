@@ -0,0 +1,79 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var suppressedSecretEnabled = NewTalismanRCIgnore([]byte("detectors:\n  suppressedsecret:\n    enabled: true\n"))
+
+func TestShouldFlagSecretsOnLinesHiddenFromSecurityTooling(t *testing.T) {
+	for _, line := range []string{
+		`password = "Tr0ub4dor&3xkcd"  # nosec`,
+		`const apiKey = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"; // eslint-disable-line`,
+		`token: dGhpcyBpcyBhIHNlY3JldCB0b2tlbiB2YWx1ZQ== # pragma: allowlist secret`,
+		`AWS_SECRET = "c2VjcmV0LWtleS10aGF0LWxvb2tzLXJlYWwtMTIz" // NOSONAR`,
+	} {
+		results := NewDetectionResults()
+		additions := []git_repo.Addition{git_repo.NewAddition("app.py", []byte("import os\n"+line+"\n"))}
+
+		NewSuppressedSecretDetector().Test(additions, suppressedSecretEnabled, results)
+
+		if assert.Len(t, results.GetFailures("app.py"), 1, "Expected %s to be flagged", line) {
+			failure := results.GetFailures("app.py")[0]
+			assert.Equal(t, "Expected line hidden from security tooling by a suppression comment to not to contain secrets such as: "+line, failure.Message)
+			assert.Equal(t, HighSeverity, failure.Severity)
+			assert.Equal(t, 2, failure.Line)
+		}
+	}
+}
+
+func TestShouldFlagTheSecretOfTheLineAfterANextLineSuppression(t *testing.T) {
+	content := "// eslint-disable-next-line\nconst clientSecret = 'wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY';\n"
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("client.js", []byte(content))}
+
+	NewSuppressedSecretDetector().Test(additions, suppressedSecretEnabled, results)
+
+	if assert.Len(t, results.GetFailures("client.js"), 1) {
+		assert.Equal(t, 2, results.GetFailures("client.js")[0].Line)
+	}
+}
+
+func TestShouldNotFlagSuppressionCommentsWithoutSecrets(t *testing.T) {
+	for _, content := range []string{
+		"subprocess.call(cmd, shell=True)  # nosec\n",
+		"import * as utils from './utils'; // eslint-disable-line no-unused-vars\n",
+		"password = os.environ[\"DB_PASSWORD\"]  # nosec\n",
+		"// eslint-disable-next-line\nconsole.log('starting');\n",
+		"# noqa\n",
+	} {
+		results := NewDetectionResults()
+		additions := []git_repo.Addition{git_repo.NewAddition("app.py", []byte(content))}
+
+		NewSuppressedSecretDetector().Test(additions, suppressedSecretEnabled, results)
+
+		assert.False(t, results.HasFailures(), "Expected %q to not be flagged", content)
+	}
+}
+
+func TestShouldNotFlagSecretsWithoutASuppressionComment(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("app.py", []byte(`password = "Tr0ub4dor&3xkcd"  # the admin password`))}
+
+	NewSuppressedSecretDetector().Test(additions, suppressedSecretEnabled, results)
+
+	assert.False(t, results.HasFailures(), "Expected the line without a suppression comment to be left to the other detectors")
+}
+
+func TestShouldNotFlagSuppressedSecretsUnlessEnabled(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("app.py", []byte(`password = "Tr0ub4dor&3xkcd"  # nosec`))}
+
+	NewChain().AddNamedDetector(SuppressedSecretDetectorName, "filecontent", NewSuppressedSecretDetector()).Test(additions, TalismanRCIgnore{}, results)
+
+	assert.False(t, results.HasFailures(), "Expected the suppressed secret detector to be off by default")
+}
@@ -0,0 +1,38 @@
+package detector
+
+//URL_SAFE_CHARS is the alphabet of base62 and URL-safe base64 tokens, which have - and _ in place of + and /
+const URL_SAFE_CHARS = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+const URL_SAFE_ENTROPY_THRESHOLD = 4.3
+const MIN_URL_SAFE_SECRET_LENGTH = 20
+
+//URLSafeDetector detects high entropy tokens in the base62 and URL-safe alphabets, which the base64 detector splits at each - and _
+type URLSafeDetector struct {
+	urlSafeMap map[string]bool
+	entropy    *Entropy
+	wordCheck  *WordCheck
+}
+
+func NewURLSafeDetector() *URLSafeDetector {
+	ud := URLSafeDetector{}
+	ud.initURLSafeMap()
+	ud.entropy = &Entropy{}
+	return &ud
+}
+
+func (ud *URLSafeDetector) initURLSafeMap() {
+	ud.urlSafeMap = map[string]bool{}
+	for i := 0; i < len(URL_SAFE_CHARS); i++ {
+		ud.urlSafeMap[string(URL_SAFE_CHARS[i])] = true
+	}
+}
+
+func (ud *URLSafeDetector) checkURLSafeEncoding(word string) string {
+	entropyCandidates := ud.entropy.GetEntropyCandidatesWithinWord(word, MIN_URL_SAFE_SECRET_LENGTH, ud.urlSafeMap)
+	for _, candidate := range entropyCandidates {
+		entropy := ud.entropy.GetShannonEntropy(candidate, URL_SAFE_CHARS)
+		if entropy > URL_SAFE_ENTROPY_THRESHOLD && !ud.wordCheck.containsWordsOnly(candidate) {
+			return word
+		}
+	}
+	return ""
+}
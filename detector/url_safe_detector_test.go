@@ -0,0 +1,38 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLSafeDetectorShouldDetectURLSafeTokens(t *testing.T) {
+	s := "Kq9xZ3mW-7vL2pR8t_Y4nB6cD1_fG5hJ0aB"
+
+	assert.Equal(t, s, NewURLSafeDetector().checkURLSafeEncoding(s))
+	assert.Equal(t, "", NewBase64Detector().checkBase64Encoding(s), "Expected the base64 detector to miss the token")
+}
+
+func TestURLSafeDetectorShouldNotDetectLowEntropyText(t *testing.T) {
+	assert.Equal(t, "", NewURLSafeDetector().checkURLSafeEncoding("get_user_account_settings_for_display"))
+}
+
+func TestShouldReportURLSafeTokens(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("config.env", []byte("TOKEN Kq9xZ3mW-7vL2pR8t_Y4nB6cD1_fG5hJ0aB"))}
+
+	NewFileContentDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.Equal(t, []string{"Expected file to not to contain URL-safe encoded texts such as: Kq9xZ3mW-7vL2pR8t_Y4nB6cD1_fG5hJ0aB"}, getFailureMessages(results, additions[0].Path))
+}
+
+func TestShouldNotReportBase64TokensTwice(t *testing.T) {
+	results := NewDetectionResults()
+	additions := []git_repo.Addition{git_repo.NewAddition("config.env", []byte("TOKEN aZ3xQ9wE7rT1yU5iO2pL8kJ4hG6fD0sMnBvCqWe"))}
+
+	NewFileContentDetector().Test(additions, TalismanRCIgnore{}, results)
+
+	assert.Equal(t, []string{"Expected file to not to contain base64 encoded texts such as: aZ3xQ9wE7rT1yU5iO2pL8kJ4hG6fD0sMnBvCqWe"}, getFailureMessages(results, additions[0].Path))
+}
@@ -0,0 +1,78 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//weakCredentialPattern matches a password field, such as password, passwd, pwd, db_password or adminPassword, along with the value assigned to it.
+//The name has to end with the password, as the common words of the wordlist are ordinary values of fields such as secretName or bypass.
+var weakCredentialPattern = regexp.MustCompile(`(?i)["']?[\w.-]*(?:passw(?:or)?d|pwd)["']?\s*[:=]\s*("[^"\n]*"|'[^'\n]*'|[^\s,;]*)`)
+
+//WeakCredentialDetector flags weak and default passwords, such as admin or changeme, assigned to password fields.
+//These values are too short and too common to be flagged by their format, yet they are real credentials.
+type WeakCredentialDetector struct {
+	words map[string]bool
+}
+
+//NewWeakCredentialDetector returns a WeakCredentialDetector that knows about the passwords of the embedded wordlist
+func NewWeakCredentialDetector() *WeakCredentialDetector {
+	words := map[string]bool{}
+	for _, word := range strings.Fields(WeakCredentialWordsString) {
+		words[strings.ToLower(word)] = true
+	}
+	return &WeakCredentialDetector{words}
+}
+
+//Test tests the contents of the Additions to ensure that they don't assign weak or default passwords to password fields.
+//The words configured under detectors.weakcredential.wordlist in the .talismanrc are flagged along with the embedded ones.
+func (wd *WeakCredentialDetector) Test(additions []git_repo.Addition, ignoreConfig TalismanRCIgnore, result *DetectionResults) {
+	cc := NewChecksumCompare(additions, ignoreConfig)
+	words := wd.wordsWith(ignoreConfig.Detectors[WeakCredentialDetectorName].Wordlist)
+	for _, addition := range additions {
+		if ignoreConfig.Deny(addition, "filecontent") || cc.IsScanNotRequired(addition) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Ignoring addition as it was specified to be ignored.")
+			result.Ignore(addition.Path, "filecontent")
+			continue
+		}
+		content := string(addition.Data)
+		for _, assignment := range ignoreConfig.reportableFindings(WeakCredentialDetectorName, addition.Path, weakCredentials(content, words), result) {
+			log.WithFields(log.Fields{
+				"filePath": addition.Path,
+			}).Info("Failing file as it assigns a weak or default password.")
+			result.FailAt(addition.Path, "filecontent", fmt.Sprintf("Expected file to not to contain weak or default passwords such as: %s", assignment), addition.Commits, HighSeverity, findingIn(addition.Data, assignment))
+		}
+	}
+}
+
+func (wd *WeakCredentialDetector) wordsWith(extraWords []string) map[string]bool {
+	if len(extraWords) == 0 {
+		return wd.words
+	}
+	words := map[string]bool{}
+	for word := range wd.words {
+		words[word] = true
+	}
+	for _, word := range extraWords {
+		words[strings.ToLower(strings.TrimSpace(word))] = true
+	}
+	return words
+}
+
+//weakCredentials returns the assignments of a word of the wordlist to a password field
+func weakCredentials(content string, words map[string]bool) []string {
+	var assignments []string
+	for _, match := range weakCredentialPattern.FindAllStringSubmatch(content, -1) {
+		if words[strings.ToLower(strings.Trim(match[1], `"'`))] {
+			assignments = append(assignments, strings.TrimSpace(match[0]))
+		}
+	}
+	return assignments
+}
@@ -0,0 +1,71 @@
+package detector
+
+import (
+	"testing"
+
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldFlagWeakPasswordsAssignedToPasswordFields(t *testing.T) {
+	for _, line := range []string{
+		`password = "admin"`,
+		`db_password: changeme`,
+		`"adminPassword": "Password1",`,
+		`ROOT_PWD=root`,
+		`ADMIN_PASSWD='secret'`,
+	} {
+		results := NewDetectionResults()
+		additions := []git_repo.Addition{git_repo.NewAddition("config.yml", []byte(line))}
+
+		NewWeakCredentialDetector().Test(additions, TalismanRCIgnore{}, results)
+
+		assert.True(t, results.HasFailures(), "Expected the weak password in %s to be flagged", line)
+	}
+}
+
+func TestShouldNotFlagReferencesOrStrongValues(t *testing.T) {
+	for _, line := range []string{
+		`password = "${ENV}"`,
+		`password = os.Getenv("PASSWORD")`,
+		`password: "{{ vault_password }}"`,
+		`username = "admin"`,
+		`password = "correct horse battery staple"`,
+	} {
+		results := NewDetectionResults()
+		additions := []git_repo.Addition{git_repo.NewAddition("config.yml", []byte(line))}
+
+		NewWeakCredentialDetector().Test(additions, TalismanRCIgnore{}, results)
+
+		assert.False(t, results.HasFailures(), "Expected %s to not be flagged", line)
+	}
+}
+
+func TestShouldNotFlagCommonWordsAssignedToFieldsThatAreNotPasswords(t *testing.T) {
+	for _, line := range []string{
+		`secretName: default`,
+		`credential_source = default`,
+		`bypass: master`,
+		`compass_branch: master`,
+		`password_policy: default`,
+		`login_user: admin`,
+	} {
+		results := NewDetectionResults()
+		additions := []git_repo.Addition{git_repo.NewAddition("config.yml", []byte(line))}
+
+		NewWeakCredentialDetector().Test(additions, TalismanRCIgnore{}, results)
+
+		assert.False(t, results.HasFailures(), "Expected %s to not be flagged", line)
+	}
+}
+
+func TestShouldFlagTheWordsAddedToTheWordlistInTheConfig(t *testing.T) {
+	results := NewDetectionResults()
+	ignores := NewTalismanRCIgnore([]byte("detectors:\n  weakcredential:\n    wordlist:\n    - acme2019\n"))
+	additions := []git_repo.Addition{git_repo.NewAddition("config.yml", []byte(`password = "ACME2019"`))}
+
+	NewWeakCredentialDetector().Test(additions, ignores, results)
+
+	assert.Equal(t, "Expected file to not to contain weak or default passwords such as: password = \"ACME2019\"", results.GetFailures("config.yml")[0].Message)
+}
@@ -0,0 +1,54 @@
+package detector
+
+//WeakCredentialWordsString lists the default and commonly used passwords that the WeakCredentialDetector flags when they are assigned to password fields
+const WeakCredentialWordsString = `
+1234
+12345
+123456
+1234567
+12345678
+123456789
+1234567890
+111111
+000000
+abc123
+admin
+admin123
+administrator
+changeit
+changeme
+default
+dragon
+guest
+iloveyou
+letmein
+login
+manager
+master
+monkey
+mysql
+oracle
+P@ssw0rd
+pass
+pass123
+passw0rd
+password
+password1
+password123
+postgres
+qwerty
+qwerty123
+raspberry
+root
+secret
+super
+superuser
+system
+test
+test123
+toor
+trustno1
+user
+vagrant
+welcome
+`
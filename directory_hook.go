@@ -2,6 +2,7 @@ package main
 
 import (
 	"io/ioutil"
+	"strings"
 	"talisman/git_repo"
 
 	log "github.com/Sirupsen/logrus"
@@ -33,6 +34,41 @@ func (p *DirectoryHook) GetFilesFromDirectory(globPattern string) []git_repo.Add
 	return result
 }
 
+//GetFilesFromList returns the files at the given paths as additions, along with the paths that could not be read
+func (p *DirectoryHook) GetFilesFromList(paths []string) ([]git_repo.Addition, []string) {
+	var result []git_repo.Addition
+	var missing []string
+
+	for _, file := range paths {
+		data, err := ReadFile(file)
+
+		if err != nil {
+			missing = append(missing, file)
+			continue
+		}
+
+		newAddition := git_repo.NewAddition(file, data)
+		result = append(result, newAddition)
+	}
+
+	return result, missing
+}
+
+//ReadPathsFromFile reads the newline separated paths listed in the given file, skipping blank lines
+func ReadPathsFromFile(listFile string) ([]string, error) {
+	contents, err := ReadFile(listFile)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, line := range strings.Split(string(contents), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths, nil
+}
+
 func ReadFile(filepath string) ([]byte, error) {
 	log.Debugf("reading file %s", filepath)
 	return ioutil.ReadFile(filepath)
@@ -88,6 +88,64 @@ func (repo GitRepo) AdditionsWithinRange(oldCommit string, newCommit string) []A
 	return result
 }
 
+//emptyTreeHash is the hash git gives to a tree without any file, which a root commit is diffed against
+const emptyTreeHash = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+//MergeAdditions returns the additions and modifications that the given merge commit brings in relative to its first parent, with their contents at the merge commit.
+//For an octopus merge these are the changes of all the merged branches together. A commit with a single parent is diffed against it, and a root commit against the empty tree.
+func (repo GitRepo) MergeAdditions(mergeCommit string) ([]Addition, error) {
+	if !repo.commandSucceeds("git", "rev-parse", "--verify", "--quiet", mergeCommit+"^{commit}") {
+		return nil, fmt.Errorf("unable to find the commit %s", mergeCommit)
+	}
+	commit := strings.TrimSpace(string(repo.executeRepoCommand("git", "rev-parse", mergeCommit+"^{commit}")))
+	firstParent := emptyTreeHash
+	if repo.commandSucceeds("git", "rev-parse", "--verify", "--quiet", commit+"^1") {
+		firstParent = commit + "^1"
+	}
+	var result []Addition
+	for _, file := range nonEmptyLines(repo.executeRepoCommand("git", "diff", "--name-only", "--diff-filter=ACM", firstParent, commit)) {
+		data := repo.executeRepoCommand("git", "show", commit+":"+file)
+		result = append(result, NewScannerAddition(file, []string{commit}, data))
+	}
+
+	log.WithFields(log.Fields{
+		"commit":    commit,
+		"additions": result,
+	}).Info("Generating the additions of a merge.")
+	return result, nil
+}
+
+//NoteAdditions returns the contents of the notes of every notes ref in a GitRepo as Additions, one per annotated object.
+//The path of each Addition names the notes ref and the SHA of the annotated object, which is also its only commit. Repos without notes have no such Additions.
+func (repo GitRepo) NoteAdditions() []Addition {
+	var result []Addition
+	for _, ref := range nonEmptyLines(repo.executeRepoCommand("git", "for-each-ref", "--format=%(refname)", "refs/notes/")) {
+		for _, note := range nonEmptyLines(repo.executeRepoCommand("git", "notes", "--ref", ref, "list")) {
+			hashes := strings.Fields(note)
+			if len(hashes) != 2 {
+				continue
+			}
+			data := repo.executeRepoCommand("git", "cat-file", "-p", hashes[0])
+			result = append(result, NewScannerAddition(fmt.Sprintf("%s:%s", ref, hashes[1]), []string{hashes[1]}, data))
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"additions": result,
+	}).Info("Generating git notes additions.")
+	return result
+}
+
+func nonEmptyLines(output []byte) []string {
+	var lines []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	return lines
+}
+
 //NewAddition returns a new Addition for a file with supplied name and contents
 func NewAddition(filePath string, content []byte) Addition {
 	return Addition{
@@ -107,8 +165,9 @@ func NewScannerAddition(filePath string, commits []string, content []byte) Addit
 }
 
 //ReadRepoFile returns the contents of the supplied relative filename by locating it in the git repo
+//Absolute filenames are read as they are
 func (repo GitRepo) ReadRepoFile(fileName string) ([]byte, error) {
-	path := filepath.Join(repo.root, fileName)
+	path := repo.pathOf(fileName)
 	log.Debugf("reading file %s", path)
 	return ioutil.ReadFile(path)
 }
@@ -116,13 +175,19 @@ func (repo GitRepo) ReadRepoFile(fileName string) ([]byte, error) {
 //ReadRepoFileOrNothing returns the contents of the supplied relative filename by locating it in the git repo.
 //If the given file cannot be located in theb repo, then an empty array of bytes is returned for the content.
 func (repo GitRepo) ReadRepoFileOrNothing(fileName string) ([]byte, error) {
-	filepath := path.Join(repo.root, fileName)
-	if _, err := os.Stat(filepath); err == nil {
+	if _, err := os.Stat(repo.pathOf(fileName)); err == nil {
 		return repo.ReadRepoFile(fileName)
 	}
 	return make([]byte, 0), nil
 }
 
+func (repo GitRepo) pathOf(fileName string) string {
+	if filepath.IsAbs(fileName) {
+		return fileName
+	}
+	return filepath.Join(repo.root, fileName)
+}
+
 //CheckIfFileExists checks if the file exists on the file system. Does not look into the file contents
 //Returns TRUE if file exists
 //Returns FALSE if the file is not found
@@ -156,6 +221,21 @@ func (a Addition) Matches(pattern string) bool {
 	return result
 }
 
+//IsRepository answers true if the root is within the working tree of a git repository
+func (repo GitRepo) IsRepository() bool {
+	return repo.commandSucceeds("git", "rev-parse", "--show-toplevel")
+}
+
+//IsTracked answers true if the file has been added to the index of the repository
+func (repo GitRepo) IsTracked(fileName string) bool {
+	return repo.commandSucceeds("git", "ls-files", "--error-unmatch", "--", fileName)
+}
+
+//IsIgnored answers true if the file is matched by the gitignore rules of the repository, whether it is tracked or not
+func (repo GitRepo) IsIgnored(fileName string) bool {
+	return repo.commandSucceeds("git", "check-ignore", "--quiet", "--no-index", "--", fileName)
+}
+
 func (repo GitRepo) TrackedFilesAsAdditions() []Addition {
 	trackedFilePaths := repo.trackedFilePaths()
 	var additions []Addition
@@ -245,6 +325,19 @@ func (repo GitRepo) fetchRawOutgoingDiff(oldCommit string, newCommit string) str
 	return string(repo.executeRepoCommand("git", "diff", gitRange, "--name-only", "--diff-filter=ACM"))
 }
 
+//commandSucceeds runs a git command that answers a question through its exit status, which executeRepoCommand would treat as a failure
+func (repo GitRepo) commandSucceeds(commandName string, args ...string) bool {
+	command := exec.Command(commandName, args...)
+	command.Dir = repo.root
+	err := command.Run()
+	log.WithFields(log.Fields{
+		"dir":     repo.root,
+		"command": fmt.Sprintf("%s %s", commandName, strings.Join(args, " ")),
+		"error":   err,
+	}).Debug("Git command executed")
+	return err == nil
+}
+
 func (repo GitRepo) executeRepoCommand(commandName string, args ...string) []byte {
 	log.WithFields(log.Fields{
 		"command": commandName,
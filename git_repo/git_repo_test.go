@@ -183,6 +183,114 @@ func TestMatchShouldAllowWildcardPatternMatches(t *testing.T) {
 	assert.False(t, file3.Matches(pattern))
 }
 
+func TestIsTrackedShouldOnlyAnswerTrueForFilesAddedToGit(t *testing.T) {
+	cleanTestData()
+	git, repo := setupOriginAndClones(testLocation, cloneLocation)
+	git.CreateFileWithContents("untracked.txt", "not added yet")
+	git.CreateFileWithContents("staged.txt", "added")
+	git.Add("staged.txt")
+
+	assert.True(t, repo.IsTracked("a.txt"))
+	assert.True(t, repo.IsTracked("staged.txt"))
+	assert.False(t, repo.IsTracked("untracked.txt"))
+}
+
+func TestIsRepositoryShouldOnlyAnswerTrueWithinAWorkingTree(t *testing.T) {
+	cleanTestData()
+	_, repo := setupOriginAndClones(testLocation, cloneLocation)
+	directory, _ := ioutil.TempDir(os.TempDir(), "talisman-not-a-repo")
+	defer os.RemoveAll(directory)
+
+	assert.True(t, repo.IsRepository())
+	assert.False(t, RepoLocatedAt(directory).IsRepository())
+}
+
+func TestIsIgnoredShouldAnswerTrueForFilesMatchedByGitignore(t *testing.T) {
+	cleanTestData()
+	git, repo := setupOriginAndClones(testLocation, cloneLocation)
+	git.CreateFileWithContents(".gitignore", "*.rc\n")
+	git.CreateFileWithContents("local.rc", "ignored")
+
+	assert.True(t, repo.IsIgnored("local.rc"))
+	assert.False(t, repo.IsIgnored("a.txt"))
+}
+
+func TestNoteAdditionsShouldReturnTheNotesWithTheAnnotatedObject(t *testing.T) {
+	cleanTestData()
+	git, repo := setupOriginAndClones(testLocation, cloneLocation)
+	commit := git.LatestCommit()
+	git.ExecCommand("git", "notes", "add", "-m", "deployed with password=hunter2", commit)
+	git.ExecCommand("git", "notes", "--ref", "review", "add", "-m", "looks good", commit)
+
+	additions := repo.NoteAdditions()
+
+	assert.Len(t, additions, 2)
+	assert.Equal(t, FilePath("refs/notes/commits:"+commit), additions[0].Path)
+	assert.Equal(t, []string{commit}, additions[0].Commits)
+	assert.Equal(t, "deployed with password=hunter2\n", string(additions[0].Data))
+	assert.Equal(t, FilePath("refs/notes/review:"+commit), additions[1].Path)
+}
+
+func TestNoteAdditionsShouldBeEmptyForReposWithoutNotes(t *testing.T) {
+	cleanTestData()
+	_, repo := setupOriginAndClones(testLocation, cloneLocation)
+	assert.Len(t, repo.NoteAdditions(), 0)
+}
+
+func TestMergeAdditionsShouldOnlyReturnWhatTheMergeBringsIn(t *testing.T) {
+	cleanTestData()
+	git, repo := setupOriginAndClones(testLocation, cloneLocation)
+	mainline := git.ExecCommand("git", "rev-parse", "--abbrev-ref", "HEAD")
+	git.ExecCommand("git", "checkout", "-b", "feature")
+	git.CreateFileWithContents("feature.txt", "password=hunter2")
+	git.AddAndcommit("feature.txt", "add feature")
+	git.ExecCommand("git", "checkout", mainline)
+	git.AppendFileContent("a.txt", "mainline change")
+	git.AddAndcommit("a.txt", "change mainline")
+	git.ExecCommand("git", "merge", "--no-ff", "-m", "merge feature", "feature")
+	merge := git.LatestCommit()
+
+	additions, err := repo.MergeAdditions(merge)
+
+	assert.NoError(t, err)
+	if assert.Len(t, additions, 1, "Expected the changes of the mainline to be left out") {
+		assert.Equal(t, FilePath("feature.txt"), additions[0].Path)
+		assert.Equal(t, "password=hunter2", string(additions[0].Data))
+		assert.Equal(t, []string{merge}, additions[0].Commits)
+	}
+}
+
+func TestMergeAdditionsOfAnOctopusMergeShouldReturnTheChangesOfAllTheMergedBranches(t *testing.T) {
+	cleanTestData()
+	git, repo := setupOriginAndClones(testLocation, cloneLocation)
+	mainline := git.ExecCommand("git", "rev-parse", "--abbrev-ref", "HEAD")
+	for _, branch := range []string{"first", "second"} {
+		git.ExecCommand("git", "checkout", "-b", branch, mainline)
+		git.CreateFileWithContents(branch+".txt", branch)
+		git.AddAndcommit(branch+".txt", "add "+branch)
+	}
+	git.ExecCommand("git", "checkout", mainline)
+	git.ExecCommand("git", "merge", "--no-ff", "-m", "merge both", "first", "second")
+
+	additions, err := repo.MergeAdditions("HEAD")
+
+	assert.NoError(t, err)
+	var paths []FilePath
+	for _, addition := range additions {
+		paths = append(paths, addition.Path)
+	}
+	assert.Equal(t, []FilePath{"first.txt", "second.txt"}, paths)
+}
+
+func TestMergeAdditionsShouldFailForUnknownCommits(t *testing.T) {
+	cleanTestData()
+	_, repo := setupOriginAndClones(testLocation, cloneLocation)
+
+	_, err := repo.MergeAdditions("no-such-commit")
+
+	assert.EqualError(t, err, "unable to find the commit no-such-commit")
+}
+
 func setupOriginAndClones(originLocation, cloneLocation string) (*git_testing.GitTesting, GitRepo) {
 	origin := RepoLocatedAt(originLocation)
 	git := git_testing.Init(origin.root)
@@ -0,0 +1,117 @@
+package github_pr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"talisman/git_repo"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//DefaultAPIURL is the base URL of the public GitHub API
+const DefaultAPIURL = "https://api.github.com"
+
+const filesPerPage = 100
+
+var nextPageLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+//Client fetches the changes of pull requests from the GitHub API
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+//pullRequestFile represents a single entry of the list of files changed in a pull request.
+//GitHub does not return a patch for binary files.
+type pullRequestFile struct {
+	FileName string `json:"filename"`
+	Status   string `json:"status"`
+	Patch    string `json:"patch"`
+}
+
+//NewClient returns a Client that talks to the GitHub API located at baseURL, authenticating with the given token
+func NewClient(baseURL string, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+	return &Client{strings.TrimRight(baseURL, "/"), token, http.DefaultClient}
+}
+
+//PullRequestAdditions returns an Addition for every file changed in the pull request, containing only the lines added by it.
+//Binary files are returned without content, so that only their names are checked. Removed files are left out.
+//The repository is expected in the owner/name form.
+func (c *Client) PullRequestAdditions(repository string, number int) ([]git_repo.Addition, error) {
+	if len(strings.Split(repository, "/")) != 2 {
+		return nil, fmt.Errorf("expected the repository in the owner/name form, got %q", repository)
+	}
+	var additions []git_repo.Addition
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d/files?per_page=%d", c.baseURL, repository, number, filesPerPage)
+	for url != "" {
+		files, nextURL, err := c.fetchFiles(url)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if file.Status == "removed" {
+				continue
+			}
+			additions = append(additions, git_repo.NewAddition(file.FileName, addedLines(file.Patch)))
+		}
+		url = nextURL
+	}
+	log.WithFields(log.Fields{
+		"repository":  repository,
+		"pullRequest": number,
+		"additions":   len(additions),
+	}).Info("Fetched pull request additions.")
+	return additions, nil
+}
+
+func (c *Client) fetchFiles(url string) ([]pullRequestFile, string, error) {
+	request, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	request.Header.Set("Accept", "application/vnd.github.v3+json")
+	if c.token != "" {
+		request.Header.Set("Authorization", "token "+c.token)
+	}
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("GitHub API responded with %s when fetching %s", response.Status, url)
+	}
+	var files []pullRequestFile
+	if err := json.NewDecoder(response.Body).Decode(&files); err != nil {
+		return nil, "", fmt.Errorf("unable to read the pull request files from %s: %v", url, err)
+	}
+	return files, nextPageURL(response.Header.Get("Link")), nil
+}
+
+func nextPageURL(linkHeader string) string {
+	match := nextPageLinkPattern.FindStringSubmatch(linkHeader)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+//addedLines filters the unified diff of a file to only the lines that were added
+func addedLines(patch string) []byte {
+	var result []byte
+	for _, line := range strings.Split(patch, "\n") {
+		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
+			result = append(result, strings.TrimPrefix(line, "+")...)
+			result = append(result, "\n"...)
+		}
+	}
+	return result
+}
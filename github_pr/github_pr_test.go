@@ -0,0 +1,66 @@
+package github_pr
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const secretPatch = "@@ -1,2 +1,3 @@\n unchanged line\n-removed line\n+aws_secret = wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY\n+another added line"
+
+func stubbedGitHub(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/owner/repo/pulls/42/files", r.URL.Path)
+		assert.Equal(t, "token some-token", r.Header.Get("Authorization"))
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `[{"filename": "images/logo.png", "status": "added"}, {"filename": "old.txt", "status": "removed", "patch": "@@ -1 +0,0 @@\n-gone"}]`)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/pulls/42/files?per_page=100&page=2>; rel="next", <%s/repos/owner/repo/pulls/42/files?per_page=100&page=2>; rel="last"`, server.URL, server.URL))
+		fmt.Fprintf(w, `[{"filename": "config/secrets.yml", "status": "modified", "patch": %q}]`, secretPatch)
+	}))
+	return server
+}
+
+func TestShouldReturnAddedLinesOfEveryPage(t *testing.T) {
+	server := stubbedGitHub(t)
+	defer server.Close()
+
+	additions, err := NewClient(server.URL, "some-token").PullRequestAdditions("owner/repo", 42)
+
+	assert.NoError(t, err)
+	assert.Len(t, additions, 2, "Expected removed files to be left out")
+	assert.Equal(t, "config/secrets.yml", string(additions[0].Path))
+	assert.Equal(t, "aws_secret = wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY\nanother added line\n", string(additions[0].Data))
+}
+
+func TestShouldReturnBinaryFilesWithoutContent(t *testing.T) {
+	server := stubbedGitHub(t)
+	defer server.Close()
+
+	additions, _ := NewClient(server.URL, "some-token").PullRequestAdditions("owner/repo", 42)
+
+	assert.Equal(t, "images/logo.png", string(additions[1].Path))
+	assert.Empty(t, additions[1].Data)
+}
+
+func TestShouldFailWhenTheAPIRespondsWithAnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "bad-token").PullRequestAdditions("owner/repo", 42)
+
+	assert.Error(t, err)
+}
+
+func TestShouldRejectRepositoriesWithoutAnOwner(t *testing.T) {
+	_, err := NewClient("", "").PullRequestAdditions("repo", 42)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,132 @@
+package redact
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"talisman/detector"
+	"talisman/git_repo"
+	"talisman/utility"
+)
+
+const (
+	//Placeholder replaces each secret in the redacted files
+	Placeholder string = "<REDACTED>"
+
+	//BackupSuffix is appended to the path of a file to name the backup of its original contents
+	BackupSuffix string = ".bak"
+)
+
+//Redaction describes the secrets that were removed from a single file
+type Redaction struct {
+	FilePath   git_repo.FilePath
+	BackupPath string
+	Count      int
+}
+
+//RedactInPlace replaces each secret that failed the detection results in the files of the working tree with the Placeholder.
+//Only the value matched by each finding, at the position the finding was recorded at, is replaced, so that the same value elsewhere in the file is left alone.
+//The original contents of each file are written to a backup next to it before the file is rewritten.
+//Failures that are not about a secret in the contents of a file, such as its name or size, are left alone.
+func RedactInPlace(results *detector.DetectionResults) ([]Redaction, error) {
+	var redactions []Redaction
+	for _, resultDetails := range results.Results {
+		var secrets []secretAt
+		for _, detail := range resultDetails.FailureList {
+			if secret := detail.SecretValue(); secret != "" && detail.Line > 0 {
+				secrets = append(secrets, secretAt{secret, detail.Secret, detail.Line, detail.EndLine, detail.Column})
+			}
+		}
+		if len(secrets) == 0 {
+			continue
+		}
+		redaction, err := redactFile(resultDetails.Filename, secrets)
+		if err != nil {
+			return redactions, err
+		}
+		if redaction.Count > 0 {
+			redactions = append(redactions, redaction)
+		}
+	}
+	return redactions, nil
+}
+
+//secretAt is the value of a secret, along with the text of the finding it was matched in and the position of that finding
+type secretAt struct {
+	value   string
+	finding string
+	line    int
+	endLine int
+	column  int
+}
+
+func redactFile(filePath git_repo.FilePath, secrets []secretAt) (Redaction, error) {
+	redaction := Redaction{FilePath: filePath, BackupPath: string(filePath) + BackupSuffix}
+	original, err := ioutil.ReadFile(string(filePath))
+	if err != nil {
+		return redaction, fmt.Errorf("unable to read %s: %s", filePath, err)
+	}
+	lines := strings.Split(string(original), "\n")
+	sort.Slice(secrets, func(i, j int) bool {
+		if secrets[i].line != secrets[j].line {
+			return secrets[i].line > secrets[j].line
+		}
+		return secrets[i].column > secrets[j].column
+	})
+	for _, secret := range secrets {
+		var redacted bool
+		if lines, redacted = redactAt(lines, secret); redacted {
+			redaction.Count++
+		}
+	}
+	if redaction.Count == 0 {
+		return redaction, nil
+	}
+	if _, err := os.Stat(redaction.BackupPath); err == nil {
+		return redaction, fmt.Errorf("unable to back up %s as %s already exists", filePath, redaction.BackupPath)
+	}
+	info, err := os.Stat(string(filePath))
+	if err != nil {
+		return redaction, err
+	}
+	if err := utility.SafeWriteFile(redaction.BackupPath, original, info.Mode().Perm()); err != nil {
+		return redaction, fmt.Errorf("unable to back up %s: %s", filePath, err)
+	}
+	if err := utility.SafeWriteFile(string(filePath), []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+		return redaction, fmt.Errorf("unable to redact %s: %s", filePath, err)
+	}
+	return redaction, nil
+}
+
+//redactAt replaces the value of the secret within the text of its finding, on the lines the finding was recorded at.
+//It answers false if the finding is no longer found there, such as when another finding already redacted it.
+func redactAt(lines []string, secret secretAt) ([]string, bool) {
+	if secret.line > len(lines) {
+		return lines, false
+	}
+	end := secret.line
+	if secret.endLine > end && secret.endLine <= len(lines) {
+		end = secret.endLine
+	}
+	region := strings.Join(lines[secret.line-1:end], "\n")
+	start := 0
+	if columnRunes := []rune(lines[secret.line-1]); secret.column > 0 && secret.column <= len(columnRunes) {
+		start = len(string(columnRunes[:secret.column-1]))
+	}
+	index := strings.Index(region[start:], secret.finding)
+	if index == -1 {
+		start = 0
+		index = strings.Index(region, secret.finding)
+	}
+	valueIndex := strings.LastIndex(secret.finding, secret.value)
+	if index == -1 || valueIndex == -1 {
+		return lines, false
+	}
+	valueStart := start + index + valueIndex
+	region = region[:valueStart] + Placeholder + region[valueStart+len(secret.value):]
+	redactedLines := append(append([]string{}, lines[:secret.line-1]...), strings.Split(region, "\n")...)
+	return append(redactedLines, lines[end:]...), true
+}
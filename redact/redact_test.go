@@ -0,0 +1,120 @@
+package redact
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"talisman/detector"
+	"talisman/git_repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const configContents = "host: db.internal\npassword: SuperSecret123!\nport: 5432\n"
+
+func TestShouldReplaceSecretsAndBackUpTheOriginal(t *testing.T) {
+	withTmpDir(func(dir string) {
+		filePath := path.Join(dir, "config.yml")
+		ioutil.WriteFile(filePath, []byte(configContents), 0600)
+		results := detectionResultsFor(filePath)
+
+		redactions, err := RedactInPlace(results)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Redaction{{FilePath: git_repo.FilePath(filePath), BackupPath: filePath + BackupSuffix, Count: 1}}, redactions)
+		redacted, _ := ioutil.ReadFile(filePath)
+		assert.Equal(t, "host: db.internal\npassword: <REDACTED>\nport: 5432\n", string(redacted), "Expected only the secret to be replaced")
+		backup, _ := ioutil.ReadFile(filePath + BackupSuffix)
+		assert.Equal(t, configContents, string(backup))
+		info, _ := os.Stat(filePath + BackupSuffix)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "Expected the backup to keep the permissions of the file")
+	})
+}
+
+func TestShouldLeaveFilesWithoutSecretsUntouched(t *testing.T) {
+	withTmpDir(func(dir string) {
+		filePath := path.Join(dir, "id_rsa")
+		ioutil.WriteFile(filePath, []byte("not really a key"), 0644)
+		results := detector.NewDetectionResults()
+		results.Fail(git_repo.FilePath(filePath), "filename", "The file name failed checks", []string{}, detector.MediumSeverity)
+
+		redactions, err := RedactInPlace(results)
+
+		assert.NoError(t, err)
+		assert.Empty(t, redactions)
+		contents, _ := ioutil.ReadFile(filePath)
+		assert.Equal(t, "not really a key", string(contents))
+		_, err = os.Stat(filePath + BackupSuffix)
+		assert.True(t, os.IsNotExist(err), "Expected no backup to be created")
+	})
+}
+
+func TestShouldNotOverwriteAnExistingBackup(t *testing.T) {
+	withTmpDir(func(dir string) {
+		filePath := path.Join(dir, "config.yml")
+		ioutil.WriteFile(filePath, []byte(configContents), 0644)
+		ioutil.WriteFile(filePath+BackupSuffix, []byte("earlier backup"), 0644)
+
+		_, err := RedactInPlace(detectionResultsFor(filePath))
+
+		assert.Error(t, err)
+		contents, _ := ioutil.ReadFile(filePath)
+		assert.Equal(t, configContents, string(contents), "Expected the file to be left as it is")
+	})
+}
+
+func TestShouldOnlyRedactTheValueAtThePositionOfTheFinding(t *testing.T) {
+	withTmpDir(func(dir string) {
+		filePath := path.Join(dir, "settings.py")
+		contents := "ROLE = \"admin\"\nADMIN_URL = \"/admin/\"\npassword = \"admin\"\n"
+		ioutil.WriteFile(filePath, []byte(contents), 0644)
+		results := detector.NewDetectionResults()
+		detector.NewWeakCredentialDetector().Test([]git_repo.Addition{git_repo.NewAddition(filePath, []byte(contents))}, detector.TalismanRCIgnore{}, results)
+
+		redactions, err := RedactInPlace(results)
+
+		assert.NoError(t, err)
+		if assert.Len(t, redactions, 1) {
+			assert.Equal(t, 1, redactions[0].Count)
+		}
+		redacted, _ := ioutil.ReadFile(filePath)
+		assert.Equal(t, "ROLE = \"admin\"\nADMIN_URL = \"/admin/\"\npassword = \"<REDACTED>\"\n", string(redacted), "Expected the other occurrences of the value to be left alone")
+	})
+}
+
+func TestShouldRedactTheValueOfAValueOnlyFindingWhereItWasMatched(t *testing.T) {
+	withTmpDir(func(dir string) {
+		filePath := path.Join(dir, "token.json")
+		contents := "{\n  \"token_hint\": \"1//0gLx9Kf3QwErTyUiOpAsDfGhJkLzXcVbNm\",\n  \"refresh_token\": \"1//0gLx9Kf3QwErTyUiOpAsDfGhJkLzXcVbNm\"\n}\n"
+		ioutil.WriteFile(filePath, []byte(contents), 0644)
+		results := detector.NewDetectionResults()
+		detector.NewOAuthTokenDetector().Test([]git_repo.Addition{git_repo.NewAddition(filePath, []byte(contents))}, detector.TalismanRCIgnore{}, results)
+
+		redactions, err := RedactInPlace(results)
+
+		assert.NoError(t, err)
+		if assert.Len(t, redactions, 1) {
+			assert.Equal(t, 1, redactions[0].Count)
+		}
+		redacted, _ := ioutil.ReadFile(filePath)
+		assert.Equal(t, "{\n  \"token_hint\": \"1//0gLx9Kf3QwErTyUiOpAsDfGhJkLzXcVbNm\",\n  \"refresh_token\": \"<REDACTED>\"\n}\n", string(redacted), "Expected the value of the refresh token to be redacted")
+	})
+}
+
+func detectionResultsFor(filePath string) *detector.DetectionResults {
+	data, _ := ioutil.ReadFile(filePath)
+	results := detector.NewDetectionResults()
+	detector.NewPatternDetector().Test([]git_repo.Addition{git_repo.NewAddition(filePath, data)}, detector.TalismanRCIgnore{}, results)
+	return results
+}
+
+func withTmpDir(f func(dir string)) {
+	dir, err := ioutil.TempDir(os.TempDir(), "talisman-redact")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	f(dir)
+}
@@ -19,8 +19,16 @@ const htmlFileName string = "report.html"
 const jsonFileName string = "report.json"
 const htmlReportDir string = "talisman_html_report"
 
+//groupedReport adds the results organized by a grouping other than by file to the scan report
+type groupedReport struct {
+	*detector.DetectionResults
+	GroupBy detector.GroupBy        `json:"group_by"`
+	Groups  []detector.ResultsGroup `json:"groups"`
+}
+
 // GenerateReport generates a talisman scan report in html format
-func GenerateReport(r *detector.DetectionResults, directory string) string {
+// Unless the results are grouped by file, the report additionally contains the results organized by the given grouping
+func GenerateReport(r *detector.DetectionResults, directory string, groupBy detector.GroupBy) string {
 
 	var path string
 	var jsonFilePath string
@@ -46,19 +54,19 @@ func GenerateReport(r *detector.DetectionResults, directory string) string {
 
 	os.MkdirAll(path, 0755)
 
-	jsonFile, err := os.Create(jsonFilePath)
-
-	if err != nil {
-		fmt.Printf("\n")
-		log.Fatal("Cannot create report.json file\n", err)
+	var reportData interface{} = r
+	if groupBy != detector.GroupByFile {
+		reportData = groupedReport{r, groupBy, r.GroupResults(groupBy)}
 	}
-
-	jsonString, err := json.Marshal(r)
+	jsonString, err := json.Marshal(reportData)
 	if err != nil {
 		log.Fatal("Unable to marshal JSON")
 	}
-	jsonFile.Write(jsonString)
-	jsonFile.Close()
+	err = utility.SafeWriteFile(jsonFilePath, jsonString, 0644)
+	if err != nil {
+		fmt.Printf("\n")
+		log.Fatal("Cannot create report.json file\n", err)
+	}
 	return path
 }
 
@@ -1,14 +1,22 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 	"talisman/checksumcalculator"
 	"talisman/detector"
 	"talisman/git_repo"
+	"talisman/redact"
 	"talisman/report"
 	"talisman/scanner"
 	"talisman/utility"
+
+	log "github.com/Sirupsen/logrus"
 )
 
 const (
@@ -19,41 +27,158 @@ const (
 	CompletedWithErrors int = 1
 )
 
+const (
+	//TextStat prints the stats of a run as text meant to be read in a terminal. This is the default format of --stat
+	TextStat string = "text"
+
+	//JSONStat prints the stats of a run as a JSON object, to be collected by dashboards
+	JSONStat string = "json"
+)
+
+const (
+	//TableFormat prints the report as tables meant to be read in a terminal. This is the default format
+	TableFormat string = "table"
+
+	//QuickfixFormat prints one path:line:col: severity: message line per detection, to be loaded into the jump list of an editor
+	QuickfixFormat string = "quickfix"
+
+	//GithubActionsFormat prints one ::error or ::warning workflow command per detection, to be rendered inline on pull requests by GitHub Actions
+	GithubActionsFormat string = "github-actions"
+)
+
 //Runner represents a single run of the validations for a given commit range
 type Runner struct {
-	additions []git_repo.Addition
-	results   *detector.DetectionResults
+	additions       []git_repo.Addition
+	results         *detector.DetectionResults
+	groupBy         detector.GroupBy
+	ignoreFile      string
+	format          string
+	configChain     []string
+	advisory        bool
+	checksumWorkers int
+	assertDetectors []string
+	stat            string
+	sampleRate      float64
+	sampleSeed      int64
+	contextDetector bool
+	deterministic   bool
+	manifest        string
+	auditLog        string
 }
 
 //NewRunner returns a new Runner.
-func NewRunner(additions []git_repo.Addition) *Runner {
+func NewRunner(additions []git_repo.Addition, _options options) *Runner {
+	groupBy, _ := detector.GroupByFromString(_options.groupBy)
+	results := detector.NewDetectionResults()
+	if _options.auditLog != "" {
+		results.EnableAuditLog()
+	}
 	return &Runner{
-		additions: additions,
-		results:   detector.NewDetectionResults(),
+		additions:       additions,
+		results:         results,
+		groupBy:         groupBy,
+		ignoreFile:      _options.ignoreFile,
+		format:          _options.format,
+		configChain:     configChainFiles(_options.configChain),
+		checksumWorkers: _options.checksumWorkers,
+		assertDetectors: commaSeparated(_options.assertDetectors),
+		stat:            _options.stat,
+		sampleRate:      _options.sample,
+		sampleSeed:      _options.sampleSeed,
+		contextDetector: _options.contextDetector,
+		deterministic:   _options.deterministic,
+		manifest:        _options.manifest,
+		auditLog:        _options.auditLog,
 	}
 }
 
 //RunWithoutErrors will validate the commit range for errors and return either COMPLETED_SUCCESSFULLY or COMPLETED_WITH_ERRORS
+//With enforce: false in the .talismanrc, the failures are reported but the run always completes successfully
+//A malformed suppress expression, severity action, allowed pattern, fixture failing severity, long line action or expiry date is a config error, which fails the run before anything is scanned
 func (r *Runner) RunWithoutErrors() int {
+	if err := r.configError(); err != nil {
+		fmt.Printf("\x1b[31mUnable to read the config: %v\x1b[0m\n", err)
+		return CompletedWithErrors
+	}
 	r.doRun()
 	r.printReport()
+	if !r.assertedDetectorsExecuted() {
+		return CompletedWithErrors
+	}
+	if r.advisory && r.results.HasFailures() {
+		if r.format == "" || r.format == TableFormat {
+			fmt.Printf("\x1b[33mTalisman is running in advisory mode (enforce: false in %s), so the above findings do not fail the run\x1b[0m\n", detector.DefaultRCFileName)
+		}
+		return CompletedSuccessfully
+	}
+	return r.exitStatus()
+}
+
+//RunStat validates the commit range like RunWithoutErrors, but only prints the stats of the run instead of its findings
+//In deterministic mode the timings of the detectors are left out, as they vary from run to run
+func (r *Runner) RunStat() int {
+	if err := r.configError(); err != nil {
+		fmt.Printf("\x1b[31mUnable to read the config: %v\x1b[0m\n", err)
+		return CompletedWithErrors
+	}
+	r.doRun()
+	stats := r.results.Stats()
+	if r.deterministic {
+		stats.WithoutTimings()
+	}
+	if r.stat == JSONStat {
+		output, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			log.Errorf("error while rendering the stats of the run: %v", err)
+			return CompletedWithErrors
+		}
+		fmt.Println(string(output))
+	} else {
+		fmt.Print(stats.Report())
+	}
+	if r.advisory {
+		return CompletedSuccessfully
+	}
 	return r.exitStatus()
 }
 
 //Scan scans git commit history for potential secrets and returns 0 or 1 as exit code
+//With a sample rate, only a random subset of the file versions in the history is scanned, and the results are labeled as sampled
 func (r *Runner) Scan(reportDirectory string) int {
 
 	fmt.Printf("\n\n")
 	utility.CreateArt("Running Scan..")
-	additions := scanner.GetAdditions()
-	ignores := detector.TalismanRCIgnore{}
+	var additions []git_repo.Addition
+	if r.sampleRate > 0 {
+		var total int
+		additions, total = scanner.GetSampledAdditions(r.sampleRate, r.sampleSeed)
+		r.results.Sample = &detector.SampleSummary{Rate: r.sampleRate, Seed: r.sampleSeed, Scanned: len(additions), Total: total}
+	} else {
+		additions = scanner.GetAdditions()
+	}
+	ignores := r.withOptions(detector.TalismanRCIgnore{})
 	detector.DefaultChain().Test(additions, ignores, r.results)
-	reportsPath := report.GenerateReport(r.results, reportDirectory)
+	r.writeAuditLog()
+	reportsPath := report.GenerateReport(r.results, reportDirectory, r.groupBy)
 	fmt.Printf("\nPlease check '%s' folder for the talisman scan report\n", reportsPath)
+	if sample := r.results.Sample; sample != nil {
+		fmt.Printf("\x1b[33mThis was a sampled scan of %d of the %d file versions in the history (rate %g, seed %d), secrets in the rest of the history are not reported\x1b[0m\n", sample.Scanned, sample.Total, sample.Rate, sample.Seed)
+	}
 	fmt.Printf("\n")
 	return r.exitStatus()
 }
 
+//RunScanNotes validates the contents of the git notes of the repository, reporting each finding against the annotated object
+func (r *Runner) RunScanNotes() int {
+	wd, _ := os.Getwd()
+	r.additions = git_repo.RepoLocatedAt(wd).NoteAdditions()
+	if len(r.additions) == 0 {
+		fmt.Println("No git notes found, nothing to scan")
+		return CompletedSuccessfully
+	}
+	return r.RunWithoutErrors()
+}
+
 //RunChecksumCalculator runs the checksum calculator against the patterns given as input
 func (r *Runner) RunChecksumCalculator(fileNamePatterns []string) int {
 	exitStatus := 1
@@ -66,11 +191,249 @@ func (r *Runner) RunChecksumCalculator(fileNamePatterns []string) int {
 	return exitStatus
 }
 
+//RunListIgnores prints the ignores, scopes, allowed patterns and suppress expressions that will be applied to the additions, along with the config file each of them was read from
+func (r *Runner) RunListIgnores() int {
+	fmt.Print(r.ignores().ListIgnores())
+	return CompletedSuccessfully
+}
+
+//RunPruneIgnores moves the expired file ignores of the .talismanrc into its archive section, leaving the rest of the file untouched
+func (r *Runner) RunPruneIgnores() int {
+	wd, _ := os.Getwd()
+	rcFilePath := filepath.Join(wd, detector.DefaultRCFileName)
+	contents, err := ioutil.ReadFile(rcFilePath)
+	if os.IsNotExist(err) {
+		fmt.Printf("No %s found, nothing to prune\n", detector.DefaultRCFileName)
+		return CompletedSuccessfully
+	} else if err != nil {
+		fmt.Printf("Unable to read %s: %s\n", rcFilePath, err)
+		return CompletedWithErrors
+	}
+	pruned, expired, err := detector.PruneExpiredIgnores(contents, time.Now())
+	if err != nil {
+		fmt.Printf("Unable to prune the ignores of %s: %s\n", rcFilePath, err)
+		return CompletedWithErrors
+	}
+	if len(expired) == 0 {
+		fmt.Println("No expired ignores found")
+		return CompletedSuccessfully
+	}
+	if err := utility.SafeWriteFile(rcFilePath, pruned, 0644); err != nil {
+		fmt.Printf("Unable to write %s: %s\n", rcFilePath, err)
+		return CompletedWithErrors
+	}
+	for _, ignore := range expired {
+		fmt.Printf("Archived expired ignore for %s (expired %s)\n", ignore.FileName, ignore.Expires)
+	}
+	return CompletedSuccessfully
+}
+
+//RunFixChecksums updates the checksums of the file ignores of the .talismanrc to those of their files, leaving the rest of the file untouched.
+//The ignores whose files are gone are reported, so that they can be removed.
+func (r *Runner) RunFixChecksums() int {
+	wd, _ := os.Getwd()
+	rcFilePath := filepath.Join(wd, detector.DefaultRCFileName)
+	contents, err := ioutil.ReadFile(rcFilePath)
+	if os.IsNotExist(err) {
+		fmt.Printf("No %s found, no checksums to fix\n", detector.DefaultRCFileName)
+		return CompletedSuccessfully
+	} else if err != nil {
+		fmt.Printf("Unable to read %s: %s\n", rcFilePath, err)
+		return CompletedWithErrors
+	}
+	var fileNames []string
+	for _, ignore := range detector.NewTalismanRCIgnore(contents).FileIgnoreConfig {
+		fileNames = append(fileNames, ignore.FileName)
+	}
+	checksums := checksumcalculator.NewChecksumCalculator(fileNames).CalculateChecksums()
+	fixed, refreshed, stale, err := detector.RefreshChecksums(contents, checksums)
+	if err != nil {
+		fmt.Printf("Unable to fix the checksums of %s: %s\n", rcFilePath, err)
+		return CompletedWithErrors
+	}
+	for _, ignore := range stale {
+		fmt.Printf("\x1b[33mNo files match the ignore for %s anymore, consider removing it from %s\x1b[0m\n", ignore.FileName, detector.DefaultRCFileName)
+	}
+	if len(refreshed) == 0 {
+		fmt.Println("No stale checksums found")
+		return CompletedSuccessfully
+	}
+	if err := utility.SafeWriteFile(rcFilePath, fixed, 0644); err != nil {
+		fmt.Printf("Unable to write %s: %s\n", rcFilePath, err)
+		return CompletedWithErrors
+	}
+	for _, ignore := range refreshed {
+		fmt.Printf("Updated the checksum of the ignore for %s\n", ignore.FileName)
+	}
+	return CompletedSuccessfully
+}
+
+//RunRedactInPlace replaces the secrets found in the additions with a placeholder, backing up each rewritten file first.
+//It does not report the findings, and only fails if the config is malformed or a file could not be redacted.
+func (r *Runner) RunRedactInPlace() int {
+	if err := r.configError(); err != nil {
+		fmt.Printf("\x1b[31mUnable to read the config: %v\x1b[0m\n", err)
+		return CompletedWithErrors
+	}
+	r.doRun()
+	redactions, err := redact.RedactInPlace(r.results)
+	for _, redaction := range redactions {
+		fmt.Printf("Redacted %d secret(s) in %s, the original was backed up as %s\n", redaction.Count, redaction.FilePath, redaction.BackupPath)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return CompletedWithErrors
+	}
+	if len(redactions) == 0 {
+		fmt.Println("No secrets found to redact")
+	}
+	return CompletedSuccessfully
+}
+
+//RunValidateConfig checks that the .talismanrc applies the same way everywhere, by being tracked by git and not matched by .gitignore.
+//A gitignored .talismanrc fails the run, while a .talismanrc that has not been added to git yet is only warned about.
+func (r *Runner) RunValidateConfig() int {
+	wd, _ := os.Getwd()
+	repo := git_repo.RepoLocatedAt(wd)
+	if !repo.CheckIfFileExists(detector.DefaultRCFileName) {
+		fmt.Printf("No %s found, nothing to validate\n", detector.DefaultRCFileName)
+		return CompletedSuccessfully
+	}
+	if repo.IsIgnored(detector.DefaultRCFileName) {
+		fmt.Printf("\x1b[31m%s is matched by .gitignore, so its ignores apply locally but not in CI or for other contributors\x1b[0m\n", detector.DefaultRCFileName)
+		return CompletedWithErrors
+	}
+	if !repo.IsTracked(detector.DefaultRCFileName) {
+		fmt.Printf("\x1b[33m%s is not tracked by git, so its ignores apply locally but not in CI or for other contributors until it is committed\x1b[0m\n", detector.DefaultRCFileName)
+		return CompletedSuccessfully
+	}
+	fmt.Printf("%s is tracked by git\n", detector.DefaultRCFileName)
+	return CompletedSuccessfully
+}
+
+//RunJSONSchema prints the JSON Schema of the .talismanrc
+func (r *Runner) RunJSONSchema() int {
+	schema, err := json.MarshalIndent(detector.TalismanRCSchema(), "", "  ")
+	if err != nil {
+		log.Errorf("error while generating the schema of %s: %v", detector.DefaultRCFileName, err)
+		return CompletedWithErrors
+	}
+	fmt.Println(string(schema))
+	return CompletedSuccessfully
+}
+
+//configError returns the first problem found in the parts of the config that are parsed before being applied
+func (r *Runner) configError() error {
+	ignores := r.ignores()
+	if _, err := ignores.SuppressRules(); err != nil {
+		return err
+	}
+	if _, err := ignores.SeverityActions(); err != nil {
+		return err
+	}
+	if _, err := ignores.AllowList(); err != nil {
+		return err
+	}
+	if _, err := ignores.FixtureFailingSeverity(); err != nil {
+		return err
+	}
+	if _, err := ignores.LongLineMode(); err != nil {
+		return err
+	}
+	return ignores.ExpiryDatesError()
+}
+
+func (r *Runner) ignores() detector.TalismanRCIgnore {
+	var rcConfigIgnores detector.TalismanRCIgnore
+	if len(r.configChain) > 0 {
+		log.Debugf("Resolved config chain, from lowest to highest priority: %s", strings.Join(r.configChain, " -> "))
+		rcConfigIgnores = detector.ReadConfigChain(configSource(), r.configChain)
+	} else {
+		rcConfigIgnores = detector.ReadConfigFromRCFile(configSource())
+	}
+	return r.withOptions(rcConfigIgnores.MergeWith(detector.ReadIgnoresFromFile(configSource(), r.ignoreFile)))
+}
+
+//withOptions applies the command line options that override the config
+func (r *Runner) withOptions(ignores detector.TalismanRCIgnore) detector.TalismanRCIgnore {
+	if r.contextDetector {
+		ignores.ContextDetector.Enabled = true
+	}
+	return ignores
+}
+
 func (r *Runner) doRun() {
-	rcConfigIgnores := detector.ReadConfigFromRCFile(readRepoFile())
+	rcConfigIgnores := r.ignores()
+	r.advisory = !rcConfigIgnores.IsEnforced()
 	scopeMap := getScopeConfig()
 	additionsToScan := detector.IgnoreAdditionsByScope(r.additions, rcConfigIgnores, scopeMap);
+	r.results.Stats().Skip(detector.SkippedOutOfScope, len(r.additions)-len(additionsToScan))
+	r.results.AuditSkipped(withoutAdditions(r.additions, additionsToScan), "scopeconfig")
+	var manifest detector.ScanManifest
+	if r.manifest != "" {
+		manifest, _ = readScanManifest(r.manifest)
+		changed := manifest.Changed(additionsToScan, rcConfigIgnores, Version)
+		r.results.Stats().Skip(detector.SkippedUnchanged, len(additionsToScan)-len(changed))
+		r.results.AuditSkipped(withoutAdditions(additionsToScan, changed), "manifest")
+		additionsToScan = changed
+	}
+	if r.checksumWorkers > 1 {
+		detector.NewChecksumCompare(additionsToScan, rcConfigIgnores).PrecomputeChecksums(r.checksumWorkers)
+	}
 	detector.DefaultChain().Test(additionsToScan, rcConfigIgnores, r.results)
+	if r.manifest != "" {
+		manifest.Record(additionsToScan, rcConfigIgnores, Version, r.results)
+		r.writeScanManifest(manifest)
+	}
+	r.writeAuditLog()
+}
+
+//withoutAdditions returns the additions that are not among the kept ones
+func withoutAdditions(additions []git_repo.Addition, kept []git_repo.Addition) []git_repo.Addition {
+	keptPaths := map[git_repo.FilePath]bool{}
+	for _, addition := range kept {
+		keptPaths[addition.Path] = true
+	}
+	var result []git_repo.Addition
+	for _, addition := range additions {
+		if !keptPaths[addition.Path] {
+			result = append(result, addition)
+		}
+	}
+	return result
+}
+
+//writeAuditLog writes the decisions recorded during the run to the audit log, if one was asked for
+func (r *Runner) writeAuditLog() {
+	if r.auditLog == "" {
+		return
+	}
+	contents, err := r.results.AuditLog()
+	if err == nil {
+		err = utility.SafeWriteFile(r.auditLog, contents, 0600)
+	}
+	if err != nil {
+		log.Errorf("error while writing the audit log %s: %v", r.auditLog, err)
+	}
+}
+
+//readScanManifest reads the manifest of the previous runs, which records no files if there is no manifest yet
+func readScanManifest(fileName string) (detector.ScanManifest, error) {
+	contents, err := ioutil.ReadFile(fileName)
+	if err != nil && !os.IsNotExist(err) {
+		return detector.ScanManifest{}, err
+	}
+	return detector.NewScanManifest(contents)
+}
+
+func (r *Runner) writeScanManifest(manifest detector.ScanManifest) {
+	contents, err := manifest.Contents()
+	if err == nil {
+		err = utility.SafeWriteFile(r.manifest, contents, 0644)
+	}
+	if err != nil {
+		log.Errorf("error while updating the manifest %s: %v", r.manifest, err)
+	}
 }
 
 func getScopeConfig() map[string][]string {
@@ -82,14 +445,47 @@ func getScopeConfig() map[string][]string {
 }
 
 func (r *Runner) printReport() {
+	if r.format == QuickfixFormat {
+		fmt.Print(r.results.QuickfixReport())
+		return
+	}
+	if r.format == GithubActionsFormat {
+		fmt.Print(r.results.GithubActionsReport())
+		return
+	}
 	if r.results.HasWarnings() {
-		fmt.Println(r.results.ReportWarnings())
+		fmt.Println(r.results.ReportWarningsGroupedBy(r.groupBy))
 	}
 	if r.results.HasIgnores() || r.results.HasFailures() {
-		fmt.Println(r.results.Report())
+		fmt.Println(r.results.ReportGroupedBy(r.groupBy))
 	}
 }
 
+//assertedDetectorsExecuted reports the detectors that executed when detectors were asserted, answering false if any of the asserted ones did not.
+//The report goes to stderr for the formats that are meant to be parsed.
+func (r *Runner) assertedDetectorsExecuted() bool {
+	if len(r.assertDetectors) == 0 {
+		return true
+	}
+	out := os.Stdout
+	if r.format != "" && r.format != TableFormat {
+		out = os.Stderr
+	}
+	executed := r.results.ExecutedDetectors()
+	fmt.Fprintf(out, "Detectors executed: %s\n", strings.Join(executed, ", "))
+	var missing []string
+	for _, name := range r.assertDetectors {
+		if !contains(executed, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(out, "\x1b[31mExpected detectors did not execute: %s. Check that %s does not ignore them for all files\x1b[0m\n", strings.Join(missing, ", "), detector.DefaultRCFileName)
+		return false
+	}
+	return true
+}
+
 func (r *Runner) exitStatus() int {
 	if r.results.HasFailures() {
 		return CompletedWithErrors
@@ -97,8 +493,7 @@ func (r *Runner) exitStatus() int {
 	return CompletedSuccessfully
 }
 
-func readRepoFile() func(string) ([]byte, error) {
+func configSource() detector.ConfigSource {
 	wd, _ := os.Getwd()
-	repo := git_repo.RepoLocatedAt(wd)
-	return repo.ReadRepoFileOrNothing
+	return detector.GitConfigSource(git_repo.RepoLocatedAt(wd))
 }
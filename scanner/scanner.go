@@ -2,7 +2,10 @@ package scanner
 
 import (
 	"log"
+	"math"
+	"math/rand"
 	"os/exec"
+	"sort"
 	"strings"
 	"talisman/git_repo"
 )
@@ -15,13 +18,56 @@ type BlobsInCommits struct {
 // GetAdditions will get all the additions for entire git history
 func GetAdditions() []git_repo.Addition {
 	blobsInCommits := getBlobsInCommit()
-	var additions []git_repo.Addition
+	return blobsInCommits.additions(blobsInCommits.blobs())
+}
+
+// GetSampledAdditions will get the additions of a random subset of the file versions in the entire git history, holding the given rate of them.
+// The same seed selects the same subset of the same history. The number of file versions the subset was taken from is returned as well.
+func GetSampledAdditions(rate float64, seed int64) ([]git_repo.Addition, int) {
+	blobsInCommits := getBlobsInCommit()
+	blobs := blobsInCommits.blobs()
+	return blobsInCommits.additions(Sample(blobs, rate, seed)), len(blobs)
+}
+
+// Sample returns a random subset of the items holding the given rate of them, rounded up, in sorted order.
+// The subset only depends on the items and the seed, whatever the order of the items.
+func Sample(items []string, rate float64, seed int64) []string {
+	sorted := append([]string(nil), items...)
+	sort.Strings(sorted)
+	count := int(math.Ceil(rate * float64(len(sorted))))
+	if count >= len(sorted) {
+		return sorted
+	}
+	picked := rand.New(rand.NewSource(seed)).Perm(len(sorted))[:count]
+	sort.Ints(picked)
+	sample := make([]string, count)
+	for i, index := range picked {
+		sample[i] = sorted[index]
+	}
+	return sample
+}
+
+// blobs returns the blobs of the history in sorted order, so that the additions are scanned and reported in the same order on every run
+func (blobsInCommits BlobsInCommits) blobs() []string {
+	var blobs []string
 	for blob := range blobsInCommits.commits {
+		blobs = append(blobs, blob)
+	}
+	sort.Strings(blobs)
+	return blobs
+}
+
+// additions reads the given blobs, with the commits each of them is present in sorted, as the commits are listed concurrently
+func (blobsInCommits BlobsInCommits) additions(blobs []string) []git_repo.Addition {
+	var additions []git_repo.Addition
+	for _, blob := range blobs {
 		objectDetails := strings.Split(blob, "\t")
 		objectHash := objectDetails[0]
 		data := getData(objectHash)
 		filePath := objectDetails[1]
-		newAddition := git_repo.NewScannerAddition(filePath, blobsInCommits.commits[blob], data)
+		commits := append([]string(nil), blobsInCommits.commits[blob]...)
+		sort.Strings(commits)
+		newAddition := git_repo.NewScannerAddition(filePath, commits, data)
 		additions = append(additions, newAddition)
 	}
 	return additions
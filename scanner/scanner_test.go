@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func blobNames(count int) []string {
+	var names []string
+	for i := 0; i < count; i++ {
+		names = append(names, fmt.Sprintf("%040d\tfile%d.txt", i, i))
+	}
+	return names
+}
+
+func TestSampleWithAFixedSeedShouldSelectTheSameSubset(t *testing.T) {
+	items := blobNames(100)
+	reversed := make([]string, len(items))
+	for i, item := range items {
+		reversed[len(items)-1-i] = item
+	}
+
+	first := Sample(items, 0.1, 42)
+
+	assert.Equal(t, first, Sample(items, 0.1, 42), "Expected the same seed to select the same subset")
+	assert.Equal(t, first, Sample(reversed, 0.1, 42), "Expected the subset to not depend on the order of the items")
+	assert.NotEqual(t, first, Sample(items, 0.1, 7), "Expected another seed to select another subset")
+}
+
+func TestSampleShouldBeBoundedByTheRate(t *testing.T) {
+	items := blobNames(95)
+
+	assert.Len(t, Sample(items, 0.1, 1), 10, "Expected 10% of 95 items to be rounded up to 10")
+	assert.Len(t, Sample(items, 0.5, 1), 48)
+	assert.Len(t, Sample(items, 0.001, 1), 1, "Expected a small rate to still sample an item")
+	assert.Len(t, Sample(items, 1, 1), 95)
+	assert.Empty(t, Sample(nil, 0.5, 1))
+}
+
+func TestSampleShouldOnlyHoldItemsOfTheInputOnce(t *testing.T) {
+	items := blobNames(50)
+	seen := map[string]bool{}
+
+	for _, item := range Sample(items, 0.4, 3) {
+		assert.Contains(t, items, item)
+		assert.False(t, seen[item], "Expected %s to be sampled once", item)
+		seen[item] = true
+	}
+}
+
+func TestBlobsShouldBeListedInTheSameOrderOnEveryRun(t *testing.T) {
+	blobsInCommits := newBlobsInCommit()
+	for _, blob := range blobNames(20) {
+		blobsInCommits.commits[blob] = []string{"b", "a"}
+	}
+
+	assert.Equal(t, blobNames(20), blobsInCommits.blobs(), "Expected the blobs to be sorted whatever the order of the map")
+}
@@ -8,7 +8,9 @@ import (
 	"io"
 	"os"
 	"strings"
+	"talisman/detector"
 	"talisman/git_repo"
+	"talisman/github_pr"
 
 	log "github.com/Sirupsen/logrus"
 )
@@ -24,6 +26,34 @@ var (
 	checksum        string
 	reportdirectory string
 	scanWithHtml    bool
+	groupBy         string
+	githubRepo      string
+	githubPR        int
+	githubToken     string
+	githubAPIURL    string
+	listIgnores     bool
+	ignoreFile      string
+	pruneIgnores    bool
+	format          string
+	redactInPlace   bool
+	confirmRedact   bool
+	validateConfig  bool
+	configChain     string
+	jsonSchema      bool
+	scanNotes       bool
+	checksumWorkers int
+	pathsFromFile   string
+	assertDetectors string
+	stat            string
+	fixChecksums    bool
+	sample          float64
+	sampleSeed      int64
+	contextDetector bool
+	deterministic   bool
+	mergeCommit     string
+	manifest        string
+	auditLog        string
+	repoRoot        string
 )
 
 const (
@@ -45,6 +75,34 @@ type options struct {
 	checksum        string
 	reportdirectory string
 	scanWithHtml    bool
+	groupBy         string
+	githubRepo      string
+	githubPR        int
+	githubToken     string
+	githubAPIURL    string
+	listIgnores     bool
+	ignoreFile      string
+	pruneIgnores    bool
+	format          string
+	redactInPlace   bool
+	confirmRedact   bool
+	validateConfig  bool
+	configChain     string
+	jsonSchema      bool
+	scanNotes       bool
+	checksumWorkers int
+	pathsFromFile   string
+	assertDetectors string
+	stat            string
+	fixChecksums    bool
+	sample          float64
+	sampleSeed      int64
+	contextDetector bool
+	deterministic   bool
+	mergeCommit     string
+	manifest        string
+	auditLog        string
+	repoRoot        string
 }
 
 //Logger is the default log device, set to emit at the Error level by default
@@ -55,15 +113,44 @@ func main() {
 	flag.BoolVar(&showVersion, "version", false, "show current version of talisman")
 	flag.StringVar(&pattern, "p", "", "short form of pattern")
 	flag.StringVar(&pattern, "pattern", "", "pattern (glob-like) of files to scan (ignores githooks)")
+	flag.StringVar(&manifest, "manifest", "", "manifest of the content hashes of the files scanned by previous runs, only the files that changed since are scanned and the manifest is updated afterward")
+	flag.StringVar(&mergeCommit, "merge-commit", "", "scan the changes that the merge commit brings in relative to its first parent (ignores githooks)")
+	flag.StringVar(&pathsFromFile, "paths-from-file", "", "file listing the paths to scan, one per line (ignores githooks)")
 	flag.StringVar(&githook, "githook", PrePush, "either pre-push or pre-commit")
 	flag.BoolVar(&scan, "s", false, "short form of scanner")
 	flag.BoolVar(&scan, "scan", false, "scanner scans the git commit history for potential secrets")
 	flag.StringVar(&checksum, "c", "", "short form of checksum calculator")
 	flag.StringVar(&checksum, "checksum", "", "checksum calculator calculates checksum and suggests .talsimarc format")
+	flag.BoolVar(&scanNotes, "scan-notes", false, "scan the contents of the git notes, reporting each finding against the SHA of the annotated object")
+	flag.Float64Var(&sample, "sample", 0, "rate between 0 and 1 of the file versions in the history to scan, picked at random for a quick check")
+	flag.Int64Var(&sampleSeed, "sample-seed", 0, "seed of the random sample of the history, the same seed picks the same sample")
+	flag.StringVar(&repoRoot, "repo-root", "", "root of the repository to scan, against which the paths, the configs and the git operations are resolved instead of the current directory")
 	flag.StringVar(&reportdirectory, "reportdirectory", "", "directory where the scan reports will be stored")
 	flag.StringVar(&reportdirectory, "rd", "", "short form of report directory")
 	flag.BoolVar(&scanWithHtml, "scanWithHtml", false, "Generate html report. (**Make sure you have installed talisman_html_report to use this, as mentioned in Readme)**")
 	flag.BoolVar(&scanWithHtml, "swh", false, "short form of html report scanner")
+	flag.StringVar(&groupBy, "group-by", string(detector.GroupByFile), "group the reported results by file, detector or severity")
+	flag.StringVar(&githubRepo, "github-repo", "", "GitHub repository (owner/name) of the pull request to scan")
+	flag.IntVar(&githubPR, "github-pr", 0, "number of the GitHub pull request to scan, fetching its changes through the GitHub API")
+	flag.StringVar(&githubToken, "github-token", os.Getenv("GITHUB_TOKEN"), "token used to access the GitHub API (defaults to $GITHUB_TOKEN)")
+	flag.StringVar(&githubAPIURL, "github-api-url", github_pr.DefaultAPIURL, "base URL of the GitHub API")
+	flag.StringVar(&auditLog, "audit-log", "", "file to write the audit log of the run to, as newline-delimited JSON recording the detectors that scanned each file, the findings and the rule that suppressed each of them")
+	flag.StringVar(&assertDetectors, "assert-detectors", "", "comma separated detectors that must execute, failing the run if any of them was skipped")
+	flag.StringVar(&stat, "stat", "", "print the stats of the run instead of its findings, as text or json")
+	flag.Lookup("stat").NoOptDefVal = TextStat
+	flag.IntVar(&checksumWorkers, "checksum-workers", 1, "number of files to hash in parallel when verifying the checksums of the file ignores")
+	flag.BoolVar(&contextDetector, "context-detector", false, "only report the high entropy findings of the content detectors that have a keyword such as password, token or key within a few lines")
+	flag.StringVar(&configChain, "config-chain", "", "comma separated config files to read instead of .talismanrc, merged in order so that later configs override earlier ones")
+	flag.BoolVar(&deterministic, "deterministic", false, "produce the same output for the same inputs, leaving out the timestamps of the logs and the timings of the stats")
+	flag.StringVar(&ignoreFile, "ignore-file", "", "legacy ignore file to read in addition to .talismanrc (defaults to .talismanignore)")
+	flag.BoolVar(&listIgnores, "list-ignores", false, "print the ignores, scopes, allowed patterns and suppress expressions that will be applied, along with the config file each was read from")
+	flag.StringVar(&format, "format", TableFormat, "format of the report printed by the git hooks and pattern scans, one of table, quickfix or github-actions")
+	flag.BoolVar(&redactInPlace, "redact-in-place", false, "replace the secrets found in the working tree with <REDACTED>, backing up each file as <file>.bak (requires --confirm-redact)")
+	flag.BoolVar(&confirmRedact, "confirm-redact", false, "confirm that the files of the working tree should be rewritten by --redact-in-place")
+	flag.BoolVar(&validateConfig, "validate-config", false, "check that .talismanrc is tracked by git and not matched by .gitignore")
+	flag.BoolVar(&jsonSchema, "json-schema", false, "print the JSON Schema of .talismanrc, for editors and CI to validate the config against")
+	flag.BoolVar(&fixChecksums, "fix-checksums", false, "update the checksums of the file ignores of .talismanrc to those of their files, reporting the ignores whose files are gone")
+	flag.BoolVar(&pruneIgnores, "prune-ignores", false, "move the expired file ignores of .talismanrc into its archive section")
 
 	flag.Parse()
 
@@ -85,6 +172,34 @@ func main() {
 		checksum:        checksum,
 		reportdirectory: reportdirectory,
 		scanWithHtml:    scanWithHtml,
+		groupBy:         groupBy,
+		githubRepo:      githubRepo,
+		githubPR:        githubPR,
+		githubToken:     githubToken,
+		githubAPIURL:    githubAPIURL,
+		listIgnores:     listIgnores,
+		ignoreFile:      ignoreFile,
+		pruneIgnores:    pruneIgnores,
+		format:          format,
+		redactInPlace:   redactInPlace,
+		confirmRedact:   confirmRedact,
+		validateConfig:  validateConfig,
+		configChain:     configChain,
+		jsonSchema:      jsonSchema,
+		scanNotes:       scanNotes,
+		checksumWorkers: checksumWorkers,
+		pathsFromFile:   pathsFromFile,
+		assertDetectors: assertDetectors,
+		stat:            stat,
+		fixChecksums:    fixChecksums,
+		sample:          sample,
+		sampleSeed:      sampleSeed,
+		contextDetector: contextDetector,
+		deterministic:   deterministic,
+		mergeCommit:     mergeCommit,
+		manifest:        manifest,
+		auditLog:        auditLog,
+		repoRoot:        repoRoot,
 	}
 
 	os.Exit(run(os.Stdin, _options))
@@ -97,20 +212,145 @@ func run(stdin io.Reader, _options options) (returnCode int) {
 		log.SetLevel(log.ErrorLevel)
 	}
 
+	if _options.deterministic {
+		defer log.SetFormatter(log.StandardLogger().Formatter)
+		log.SetFormatter(&log.TextFormatter{DisableTimestamp: true, DisableColors: true})
+	}
+
 	if _options.githook == "" {
 		_options.githook = PrePush
 	}
 
+	if _options.repoRoot != "" {
+		wd, err := changeToRepoRoot(_options.repoRoot, _options.pattern != "" || _options.pathsFromFile != "")
+		if err != nil {
+			fmt.Println(err)
+			return CompletedWithErrors
+		}
+		defer os.Chdir(wd)
+	}
+
+	if _, err := detector.GroupByFromString(_options.groupBy); err != nil {
+		fmt.Println(err)
+		return CompletedWithErrors
+	}
+
+	if _options.format != "" && _options.format != TableFormat && _options.format != QuickfixFormat && _options.format != GithubActionsFormat {
+		fmt.Printf("unknown format %q, expected one of table, quickfix or github-actions\n", _options.format)
+		return CompletedWithErrors
+	}
+
+	if _options.stat != "" && _options.stat != TextStat && _options.stat != JSONStat {
+		fmt.Printf("unknown stat format %q, expected one of text or json\n", _options.stat)
+		return CompletedWithErrors
+	}
+
+	if _options.sample < 0 || _options.sample > 1 {
+		fmt.Printf("invalid sample rate %g, expected a rate between 0 and 1\n", _options.sample)
+		return CompletedWithErrors
+	}
+
+	if _options.sample > 0 && !_options.scan && !_options.scanWithHtml {
+		fmt.Println("--sample only applies to the scans of the history, run it along with --scan or --scanWithHtml")
+		return CompletedWithErrors
+	}
+
+	if _options.ignoreFile != "" && !fileExists(_options.ignoreFile) {
+		fmt.Printf("Unable to find the ignore file %s\n", _options.ignoreFile)
+		return CompletedWithErrors
+	}
+
+	for _, configFile := range configChainFiles(_options.configChain) {
+		if !fileExists(configFile) {
+			fmt.Printf("Unable to find the config file %s of the config chain\n", configFile)
+			return CompletedWithErrors
+		}
+	}
+
+	if _options.manifest != "" {
+		if _, err := readScanManifest(_options.manifest); err != nil {
+			fmt.Printf("Unable to read the manifest %s: %v\n", _options.manifest, err)
+			return CompletedWithErrors
+		}
+	}
+
+	knownDetectors := detector.DefaultChain().DetectorNames()
+	for _, name := range commaSeparated(_options.assertDetectors) {
+		if !contains(knownDetectors, name) {
+			fmt.Printf("unknown detector %q, expected one of %s\n", name, strings.Join(knownDetectors, ", "))
+			return CompletedWithErrors
+		}
+	}
+
 	var additions []git_repo.Addition
-	if _options.checksum != "" {
+	if _options.redactInPlace {
+		if !_options.confirmRedact {
+			fmt.Println("--redact-in-place rewrites the files of the working tree, run it again with --confirm-redact to proceed")
+			return CompletedWithErrors
+		}
+		log.Infof("Redacting secrets in place")
+		if _options.pattern != "" {
+			additions = NewDirectoryHook().GetFilesFromDirectory(_options.pattern)
+		} else {
+			additions = trackedFilesWithContents()
+		}
+		return NewRunner(additions, _options).RunRedactInPlace()
+	} else if _options.jsonSchema {
+		log.Infof("Printing the schema of %s", detector.DefaultRCFileName)
+		return NewRunner(make([]git_repo.Addition, 0), _options).RunJSONSchema()
+	} else if _options.validateConfig {
+		log.Infof("Validating %s", detector.DefaultRCFileName)
+		return NewRunner(make([]git_repo.Addition, 0), _options).RunValidateConfig()
+	} else if _options.listIgnores {
+		log.Infof("Listing effective ignores")
+		return NewRunner(make([]git_repo.Addition, 0), _options).RunListIgnores()
+	} else if _options.pruneIgnores {
+		log.Infof("Pruning expired ignores")
+		return NewRunner(make([]git_repo.Addition, 0), _options).RunPruneIgnores()
+	} else if _options.fixChecksums {
+		log.Infof("Fixing the checksums of %s", detector.DefaultRCFileName)
+		return NewRunner(make([]git_repo.Addition, 0), _options).RunFixChecksums()
+	} else if _options.checksum != "" {
 		log.Infof("Running %s patterns against checksum calculator", _options.checksum)
-		return NewRunner(make([]git_repo.Addition, 0)).RunChecksumCalculator(strings.Fields(_options.checksum))
+		return NewRunner(make([]git_repo.Addition, 0), _options).RunChecksumCalculator(strings.Fields(_options.checksum))
 	} else if _options.scan {
 		log.Infof("Running scanner")
-		return NewRunner(make([]git_repo.Addition, 0)).Scan(_options.reportdirectory)
+		return NewRunner(make([]git_repo.Addition, 0), _options).Scan(_options.reportdirectory)
+	} else if _options.scanNotes {
+		log.Infof("Running against git notes")
+		return NewRunner(make([]git_repo.Addition, 0), _options).RunScanNotes()
 	} else if _options.scanWithHtml {
 		log.Infof("Running scanner with html report")
-		return NewRunner(make([]git_repo.Addition, 0)).Scan("talisman_html_report")
+		return NewRunner(make([]git_repo.Addition, 0), _options).Scan("talisman_html_report")
+	} else if _options.githubPR != 0 {
+		log.Infof("Running against pull request %s#%d", _options.githubRepo, _options.githubPR)
+		var err error
+		additions, err = github_pr.NewClient(_options.githubAPIURL, _options.githubToken).PullRequestAdditions(_options.githubRepo, _options.githubPR)
+		if err != nil {
+			fmt.Println(err)
+			return CompletedWithErrors
+		}
+	} else if _options.mergeCommit != "" {
+		log.Infof("Running against the changes merged by %s", _options.mergeCommit)
+		wd, _ := os.Getwd()
+		var err error
+		additions, err = git_repo.RepoLocatedAt(wd).MergeAdditions(_options.mergeCommit)
+		if err != nil {
+			fmt.Println(err)
+			return CompletedWithErrors
+		}
+	} else if _options.pathsFromFile != "" {
+		log.Infof("Running against the paths listed in %s", _options.pathsFromFile)
+		paths, err := ReadPathsFromFile(_options.pathsFromFile)
+		if err != nil {
+			fmt.Printf("Unable to read the list of paths %s\n", _options.pathsFromFile)
+			return CompletedWithErrors
+		}
+		var missing []string
+		additions, missing = NewDirectoryHook().GetFilesFromList(paths)
+		for _, path := range missing {
+			fmt.Printf("\x1b[33mSkipping %s listed in %s as it could not be read\x1b[0m\n", path, _options.pathsFromFile)
+		}
 	} else if _options.pattern != "" {
 		log.Infof("Running %s pattern", _options.pattern)
 		directoryHook := NewDirectoryHook()
@@ -125,7 +365,67 @@ func run(stdin io.Reader, _options options) (returnCode int) {
 		additions = prePushHook.GetRepoAdditions()
 	}
 
-	return NewRunner(additions).RunWithoutErrors()
+	if _options.stat != "" {
+		return NewRunner(additions, _options).RunStat()
+	}
+	return NewRunner(additions, _options).RunWithoutErrors()
+}
+
+func trackedFilesWithContents() []git_repo.Addition {
+	wd, _ := os.Getwd()
+	var additions []git_repo.Addition
+	for _, tracked := range git_repo.RepoLocatedAt(wd).TrackedFilesAsAdditions() {
+		data, err := ReadFile(string(tracked.Path))
+		if err != nil {
+			continue
+		}
+		additions = append(additions, git_repo.NewAddition(string(tracked.Path), data))
+	}
+	return additions
+}
+
+func configChainFiles(configChain string) []string {
+	return commaSeparated(configChain)
+}
+
+func commaSeparated(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+//changeToRepoRoot makes the repo root the working directory of the run, so that the paths, configs and git operations are all resolved against it.
+//The repo root has to be a git repository, or any directory when scanning the filesystem. It returns the directory to change back to.
+func changeToRepoRoot(repoRoot string, filesystemMode bool) (string, error) {
+	if info, err := os.Stat(repoRoot); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("Unable to find the repo root directory %s", repoRoot)
+	}
+	if !filesystemMode && !git_repo.RepoLocatedAt(repoRoot).IsRepository() {
+		return "", fmt.Errorf("The repo root %s is not a git repository", repoRoot)
+	}
+	wd, err := os.Getwd()
+	if err == nil {
+		err = os.Chdir(repoRoot)
+	}
+	return wd, err
+}
+
+func fileExists(fileName string) bool {
+	_, err := os.Stat(fileName)
+	return err == nil
 }
 
 func readRefAndSha(file io.Reader) (string, string, string, string) {
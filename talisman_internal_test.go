@@ -22,3 +22,19 @@ func TestParsingShasFromStdIn(t *testing.T) {
 	assert.Equal(t, "localSha", oldSha, "oldSha did not equal 'localSha', got: %s", oldSha)
 	assert.Equal(t, "remoteSha", newSha, "newSha did not equal 'remoteSha', got: %s", newSha)
 }
+
+func TestGetFilesFromListShouldReportTheListedFilesThatDoNotExist(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "listedFile")
+	if err != nil {
+		panic(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	file.WriteString("contents")
+
+	additions, missing := NewDirectoryHook().GetFilesFromList([]string{file.Name(), "does/not/exist.txt"})
+
+	assert.Len(t, additions, 1)
+	assert.Equal(t, "contents", string(additions[0].Data))
+	assert.Equal(t, []string{"does/not/exist.txt"}, missing)
+}
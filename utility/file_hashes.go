@@ -0,0 +1,76 @@
+package utility
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+//fileHash is the hash of the contents of a file, along with the size and modification time the file had when it was hashed
+type fileHash struct {
+	size    int64
+	modTime time.Time
+	hash    string
+}
+
+//fileHashCache caches the hashes of file contents by path. A cached hash is only used while the size and modification time of the file are unchanged,
+//so that a file is hashed again as soon as its contents change. A file modified within racyModTimeWindow of being hashed is not cached at all,
+//since a same-size rewrite within the granularity of its modification time would leave both unchanged and the cached hash stale.
+type fileHashCache struct {
+	mutex  sync.Mutex
+	hashes map[string]fileHash
+}
+
+//racyModTimeWindow is the granularity of modification times that the cache allows for
+const racyModTimeWindow = time.Second
+
+var fileHashes = &fileHashCache{hashes: map[string]fileHash{}}
+
+func (c *fileHashCache) hashOf(filePath string) string {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		fileBytes, _ := ioutil.ReadFile(filePath)
+		return hashByte(&fileBytes)
+	}
+	c.mutex.Lock()
+	cached, ok := c.hashes[filePath]
+	c.mutex.Unlock()
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.hash
+	}
+	fileBytes, _ := ioutil.ReadFile(filePath)
+	hash := hashByte(&fileBytes)
+	c.mutex.Lock()
+	if time.Since(info.ModTime()) > racyModTimeWindow {
+		c.hashes[filePath] = fileHash{info.Size(), info.ModTime(), hash}
+	} else {
+		delete(c.hashes, filePath)
+	}
+	c.mutex.Unlock()
+	return hash
+}
+
+//HashFilesInParallel hashes the contents of the files at the given paths with the given number of workers.
+//The hashes are cached, so that the checksums calculated afterwards do not read the files again, unless a file was modified within racyModTimeWindow.
+func HashFilesInParallel(paths []string, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	pending := make(chan string)
+	var waitGroup sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for filePath := range pending {
+				fileHashes.hashOf(filePath)
+			}
+		}()
+	}
+	for _, filePath := range UniqueItems(paths) {
+		pending <- filePath
+	}
+	close(pending)
+	waitGroup.Wait()
+}
@@ -25,7 +25,37 @@ func UniqueItems(stringSlice []string) []string {
 	return list
 }
 
+//ChecksumProvider calculates the collective checksum of the files at the given paths, with which the file ignores of .talismanrc are both written and verified
+type ChecksumProvider interface {
+	CollectiveChecksum(paths []string) string
+}
+
+//SHA256ChecksumProvider is the default ChecksumProvider, calculating the checksums with CollectiveSHA256Hash
+type SHA256ChecksumProvider struct{}
+
+//CollectiveChecksum returns the collective sha256 hash of the passed paths
+func (SHA256ChecksumProvider) CollectiveChecksum(paths []string) string {
+	return CollectiveSHA256Hash(paths)
+}
+
+var checksumProvider ChecksumProvider = SHA256ChecksumProvider{}
+
+//SetChecksumProvider replaces the ChecksumProvider used wherever a checksum is calculated, such as with a provider backed by an HSM.
+//A nil provider restores the SHA256ChecksumProvider. It is meant to be called once, before any checksum is calculated.
+func SetChecksumProvider(provider ChecksumProvider) {
+	if provider == nil {
+		provider = SHA256ChecksumProvider{}
+	}
+	checksumProvider = provider
+}
+
+//CollectiveChecksum returns the collective checksum of the passed paths, as calculated by the current ChecksumProvider
+func CollectiveChecksum(paths []string) string {
+	return checksumProvider.CollectiveChecksum(paths)
+}
+
 //CollectiveSHA256Hash return collective sha256 hash of the passed paths
+//The hashes of the file contents are cached until the files change
 func CollectiveSHA256Hash(paths []string) string {
 	var finHash = ""
 	for _, path := range paths {
@@ -33,8 +63,7 @@ func CollectiveSHA256Hash(paths []string) string {
 		concatBytes := hashByte(&sbyte)
 		nameByte := []byte(path)
 		nameHash := hashByte(&nameByte)
-		fileBytes, _ := ioutil.ReadFile(path)
-		fileHash := hashByte(&fileBytes)
+		fileHash := fileHashes.hashOf(path)
 		finHash = concatBytes + fileHash + nameHash
 	}
 	c := []byte(finHash)
@@ -115,3 +144,41 @@ func Dir(src string, dst string) error {
 	}
 	return nil
 }
+
+//writeContents writes the data to the file. It is a variable so that tests can simulate a write being interrupted.
+var writeContents = func(file *os.File, data []byte) error {
+	_, err := file.Write(data)
+	return err
+}
+
+//SafeWriteFile writes the data to the file at the given path atomically, so that an interrupted write never leaves a partially written file behind.
+//The data is written to a temporary file in the same directory, which then replaces the original file.
+//An existing file keeps its permissions, while a new file is created with the given permissions.
+func SafeWriteFile(filePath string, data []byte, perm os.FileMode) (err error) {
+	if info, statErr := os.Stat(filePath); statErr == nil {
+		perm = info.Mode().Perm()
+	}
+	tempFile, err := ioutil.TempFile(path.Dir(filePath), "."+path.Base(filePath)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tempFile.Close()
+			os.Remove(tempFile.Name())
+		}
+	}()
+	if err = writeContents(tempFile, data); err != nil {
+		return err
+	}
+	if err = tempFile.Sync(); err != nil {
+		return err
+	}
+	if err = tempFile.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tempFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tempFile.Name(), filePath)
+}
@@ -0,0 +1,142 @@
+package utility
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeWriteFileShouldReplaceTheContents(t *testing.T) {
+	withTmpDir(func(dir string) {
+		filePath := path.Join(dir, ".talismanrc")
+		ioutil.WriteFile(filePath, []byte("old contents"), 0600)
+
+		err := SafeWriteFile(filePath, []byte("new contents"), 0644)
+
+		assert.NoError(t, err)
+		contents, _ := ioutil.ReadFile(filePath)
+		assert.Equal(t, "new contents", string(contents))
+		info, _ := os.Stat(filePath)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "Expected the permissions of the existing file to be kept")
+	})
+}
+
+func TestSafeWriteFileShouldCreateNewFilesWithTheGivenPermissions(t *testing.T) {
+	withTmpDir(func(dir string) {
+		filePath := path.Join(dir, ".talismanrc")
+
+		err := SafeWriteFile(filePath, []byte("contents"), 0640)
+
+		assert.NoError(t, err)
+		info, _ := os.Stat(filePath)
+		assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+	})
+}
+
+func TestInterruptedSafeWriteFileShouldLeaveTheOriginalIntact(t *testing.T) {
+	withTmpDir(func(dir string) {
+		filePath := path.Join(dir, ".talismanrc")
+		ioutil.WriteFile(filePath, []byte("original contents"), 0644)
+		originalWriteContents := writeContents
+		defer func() { writeContents = originalWriteContents }()
+		writeContents = func(file *os.File, data []byte) error {
+			file.Write(data[:3])
+			return errors.New("interrupted")
+		}
+
+		err := SafeWriteFile(filePath, []byte("new contents"), 0644)
+
+		assert.Error(t, err)
+		contents, _ := ioutil.ReadFile(filePath)
+		assert.Equal(t, "original contents", string(contents))
+		files, _ := ioutil.ReadDir(dir)
+		assert.Len(t, files, 1, "Expected the temporary file to be cleaned up")
+	})
+}
+
+func withTmpDir(operation func(dir string)) {
+	dir, err := ioutil.TempDir(os.TempDir(), "talisman-utility-test")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	operation(dir)
+}
+
+func TestCollectiveSHA256HashShouldRehashAChangedFile(t *testing.T) {
+	withTmpDir(func(dir string) {
+		filePath := path.Join(dir, "secret.pem")
+		ioutil.WriteFile(filePath, []byte("original contents"), 0644)
+		original := CollectiveSHA256Hash([]string{filePath})
+		assert.Equal(t, original, CollectiveSHA256Hash([]string{filePath}), "Expected the hash of an unchanged file to be stable")
+
+		ioutil.WriteFile(filePath, []byte("changed contents!"), 0644)
+
+		changed := CollectiveSHA256Hash([]string{filePath})
+		assert.NotEqual(t, original, changed, "Expected a same-size rewrite to be hashed again")
+		ioutil.WriteFile(filePath, []byte("original contents"), 0644)
+		assert.Equal(t, original, CollectiveSHA256Hash([]string{filePath}), "Expected a second same-size rewrite to be hashed again")
+	})
+}
+
+func TestFileHashesShouldOnlyCacheFilesModifiedBeforeTheRacyWindow(t *testing.T) {
+	withTmpDir(func(dir string) {
+		filePath := path.Join(dir, "secret.pem")
+		ioutil.WriteFile(filePath, []byte("contents"), 0644)
+
+		fileHashes.hashOf(filePath)
+		_, cachedRecent := fileHashes.hashes[filePath]
+		modTime := time.Now().Add(-time.Minute)
+		os.Chtimes(filePath, modTime, modTime)
+		fileHashes.hashOf(filePath)
+		_, cachedOld := fileHashes.hashes[filePath]
+
+		assert.False(t, cachedRecent, "Expected a file modified just now to not be cached")
+		assert.True(t, cachedOld, "Expected a file modified a minute ago to be cached")
+	})
+}
+
+func TestHashFilesInParallelShouldCacheTheSameHashesAsSerialHashing(t *testing.T) {
+	withTmpDir(func(dir string) {
+		var paths []string
+		for i := 0; i < 20; i++ {
+			filePath := path.Join(dir, fmt.Sprintf("file%d.pem", i))
+			ioutil.WriteFile(filePath, []byte(fmt.Sprintf("contents %d", i)), 0644)
+			paths = append(paths, filePath)
+		}
+		var expected []string
+		for _, filePath := range paths {
+			fileBytes, _ := ioutil.ReadFile(filePath)
+			expected = append(expected, hashByte(&fileBytes))
+		}
+
+		HashFilesInParallel(paths, 4)
+
+		for i, filePath := range paths {
+			assert.Equal(t, expected[i], fileHashes.hashOf(filePath))
+		}
+	})
+}
+
+type fakeChecksumProvider struct{}
+
+func (fakeChecksumProvider) CollectiveChecksum(paths []string) string {
+	return "fake:" + strings.Join(paths, ",")
+}
+
+func TestCollectiveChecksumShouldUseTheChecksumProvider(t *testing.T) {
+	SetChecksumProvider(fakeChecksumProvider{})
+	defer SetChecksumProvider(nil)
+
+	assert.Equal(t, "fake:a.txt,b.txt", CollectiveChecksum([]string{"a.txt", "b.txt"}))
+
+	SetChecksumProvider(nil)
+	assert.Equal(t, CollectiveSHA256Hash([]string{"a.txt"}), CollectiveChecksum([]string{"a.txt"}), "Expected a nil provider to restore the sha256 checksums")
+}